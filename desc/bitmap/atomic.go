@@ -0,0 +1,107 @@
+package bitmap
+
+import (
+	"iter"
+	"math/bits"
+	"sync/atomic"
+)
+
+// AtomicBitmap is a fixed size bitmap that is safe for concurrent use
+// without locking. Every operation on a single bit is atomic; Count and All
+// read the words one at a time and so only see a consistent picture when no
+// other goroutine is writing. Like Bitmap, a bit at or beyond Len reads as
+// clear; writing one panics, as the bitmap cannot grow.
+type AtomicBitmap struct {
+	words []atomic.Uint64
+	n     uint
+}
+
+// NewAtomicBitmap creates an AtomicBitmap holding the bits 0..n-1
+func NewAtomicBitmap(n uint) *AtomicBitmap {
+	return &AtomicBitmap{
+		words: make([]atomic.Uint64, (n+wordMask)>>wordShift),
+		n:     n,
+	}
+}
+
+func (b *AtomicBitmap) word(num uint) (*atomic.Uint64, uint64) {
+	if num >= b.n {
+		panic("bitmap: AtomicBitmap index out of range")
+	}
+	i, mask := wordIndex(num)
+	return &b.words[i], mask
+}
+
+// Len returns the number of bits the bitmap holds
+func (b *AtomicBitmap) Len() uint {
+	return b.n
+}
+
+// Set sets the bit num
+func (b *AtomicBitmap) Set(num uint) {
+	w, mask := b.word(num)
+	w.Or(mask)
+}
+
+// Clear clears the bit num
+func (b *AtomicBitmap) Clear(num uint) {
+	w, mask := b.word(num)
+	w.And(^mask)
+}
+
+// Del is an alias of Clear
+func (b *AtomicBitmap) Del(num uint) {
+	b.Clear(num)
+}
+
+// Test reports whether the bit num is set, false when num is at or beyond
+// Len
+func (b *AtomicBitmap) Test(num uint) bool {
+	if num >= b.n {
+		return false
+	}
+	w, mask := b.word(num)
+	return w.Load()&mask != 0
+}
+
+// Check is an alias of Test
+func (b *AtomicBitmap) Check(num uint) bool {
+	return b.Test(num)
+}
+
+// TestAndSet sets the bit num and reports whether it was already set, so
+// exactly one of several goroutines setting the same bit sees false
+func (b *AtomicBitmap) TestAndSet(num uint) bool {
+	w, mask := b.word(num)
+	return w.Or(mask)&mask != 0
+}
+
+// TestAndClear clears the bit num and reports whether it was set
+func (b *AtomicBitmap) TestAndClear(num uint) bool {
+	w, mask := b.word(num)
+	return w.And(^mask)&mask != 0
+}
+
+// Count returns the number of set bits
+func (b *AtomicBitmap) Count() int {
+	n := 0
+	for i := range b.words {
+		n += bits.OnesCount64(b.words[i].Load())
+	}
+	return n
+}
+
+// All returns an iterator over the set bits in ascending order
+func (b *AtomicBitmap) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i := range b.words {
+			w := b.words[i].Load()
+			for w != 0 {
+				if !yield(uint(i)<<wordShift + uint(bits.TrailingZeros64(w))) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
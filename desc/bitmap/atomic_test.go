@@ -0,0 +1,142 @@
+package bitmap
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAtomicBitmap(t *testing.T) {
+	b := NewAtomicBitmap(130)
+	if b.Len() != 130 || b.Count() != 0 {
+		t.Fatalf("Len() = %d, Count() = %d", b.Len(), b.Count())
+	}
+	for _, v := range []uint{0, 63, 64, 129} {
+		b.Set(v)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, []uint{0, 63, 64, 129}) {
+		t.Errorf("All() = %v", got)
+	}
+	if !b.Test(63) || b.Test(62) || !b.Check(129) {
+		t.Error("Test() reports wrong bits")
+	}
+
+	tests := []struct {
+		name string
+		op   func(uint) bool
+		num  uint
+		want bool
+		set  bool
+	}{
+		{name: "set clear bit", op: b.TestAndSet, num: 1, want: false, set: true},
+		{name: "set set bit", op: b.TestAndSet, num: 1, want: true, set: true},
+		{name: "clear set bit", op: b.TestAndClear, num: 64, want: true, set: false},
+		{name: "clear clear bit", op: b.TestAndClear, num: 64, want: false, set: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op(tt.num); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if b.Test(tt.num) != tt.set {
+				t.Errorf("bit %d set = %v, want %v", tt.num, !tt.set, tt.set)
+			}
+		})
+	}
+
+	b.Clear(0)
+	b.Del(129)
+	if got := slices.Collect(b.All()); !slices.Equal(got, []uint{1, 63}) {
+		t.Errorf("after Clear All() = %v", got)
+	}
+	for range b.All() {
+		break
+	}
+}
+
+func TestAtomicBitmap_OutOfRange(t *testing.T) {
+	b := NewAtomicBitmap(64)
+	ops := map[string]func(uint){
+		"Set":          b.Set,
+		"Clear":        b.Clear,
+		"TestAndSet":   func(n uint) { b.TestAndSet(n) },
+		"TestAndClear": func(n uint) { b.TestAndClear(n) },
+	}
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			op(64)
+		})
+	}
+	// reads beyond Len see a clear bit, like Bitmap and Roaring
+	for _, n := range []uint{64, 65, 1 << 20} {
+		if b.Test(n) || b.Check(n) {
+			t.Errorf("Test(%d) = true beyond Len", n)
+		}
+	}
+	if NewAtomicBitmap(0).Check(0) {
+		t.Error("Check(0) = true on an empty bitmap")
+	}
+}
+
+func TestAtomicBitmap_Concurrent(t *testing.T) {
+	const (
+		workers = 8
+		n       = 10000
+	)
+	b := NewAtomicBitmap(n)
+	// every worker claims every bit, each bit must be won exactly once
+	var won atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uint(0); i < n; i++ {
+				if !b.TestAndSet(i) {
+					won.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if won.Load() != n || b.Count() != n {
+		t.Fatalf("won %d bits, Count() = %d, want %d", won.Load(), b.Count(), n)
+	}
+
+	// neighbouring bits share words, concurrent clears must not lose updates
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := uint(w); i < n; i += workers {
+				if w%2 == 0 {
+					b.Clear(i)
+				} else if !b.TestAndClear(i) {
+					t.Errorf("bit %d was not set", i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if c := b.Count(); c != 0 {
+		t.Errorf("Count() = %d after clearing everything", c)
+	}
+}
+
+func BenchmarkAtomicBitmap_TestAndSet(b *testing.B) {
+	const n = 1 << 20
+	bm := NewAtomicBitmap(n)
+	b.RunParallel(func(pb *testing.PB) {
+		i := uint(0)
+		for pb.Next() {
+			bm.TestAndSet(i % n)
+			i += 7
+		}
+	})
+}
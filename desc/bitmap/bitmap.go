@@ -1,45 +1,182 @@
 package bitmap
 
-type bitMap struct {
-	vmax  uint
-	graph []byte
+import (
+	"iter"
+	"math/bits"
+)
+
+const (
+	wordBits  = 64
+	wordShift = 6
+	wordMask  = wordBits - 1
+)
+
+// Bitmap is a set of non-negative integers stored as one bit per value in
+// 64-bit words. It grows on demand when a value beyond its length is set;
+// values beyond its length read as clear. A Bitmap is not safe for
+// concurrent use, see AtomicBitmap for that.
+type Bitmap struct {
+	words []uint64
 }
 
-func NewBitMap(max uint) *bitMap {
+// NewBitMap creates a Bitmap with room for the values 0..max without
+// growing. A max of 0 selects a default of 8192.
+func NewBitMap(max uint) *Bitmap {
 	var defMax uint = 8192
 	if max > 0 {
 		defMax = max
 	}
-	size := (max + 7) >> 3
-	return &bitMap{
-		vmax:  defMax,
-		graph: make([]byte, size, size),
+	return &Bitmap{
+		words: make([]uint64, defMax>>wordShift+1),
+	}
+}
+
+func wordIndex(num uint) (int, uint64) {
+	return int(num >> wordShift), 1 << (num & wordMask)
+}
+
+// grow makes room for the word at index i
+func (b *Bitmap) grow(i int) {
+	if i >= len(b.words) {
+		b.words = append(b.words, make([]uint64, i+1-len(b.words))...)
+	}
+}
+
+// Len returns the number of bits the bitmap can hold without growing
+func (b *Bitmap) Len() uint {
+	return uint(len(b.words)) << wordShift
+}
+
+// Set sets the bit num, growing the bitmap if needed
+func (b *Bitmap) Set(num uint) {
+	i, mask := wordIndex(num)
+	b.grow(i)
+	b.words[i] |= mask
+}
+
+// Clear clears the bit num
+func (b *Bitmap) Clear(num uint) {
+	if i, mask := wordIndex(num); i < len(b.words) {
+		b.words[i] &^= mask
+	}
+}
+
+// Del is an alias of Clear
+func (b *Bitmap) Del(num uint) {
+	b.Clear(num)
+}
+
+// Test reports whether the bit num is set
+func (b *Bitmap) Test(num uint) bool {
+	i, mask := wordIndex(num)
+	return i < len(b.words) && b.words[i]&mask != 0
+}
+
+// Check is an alias of Test
+func (b *Bitmap) Check(num uint) bool {
+	return b.Test(num)
+}
+
+// Flip toggles the bit num, growing the bitmap if needed
+func (b *Bitmap) Flip(num uint) {
+	i, mask := wordIndex(num)
+	b.grow(i)
+	b.words[i] ^= mask
+}
+
+// Count returns the number of set bits
+func (b *Bitmap) Count() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// NextSet returns the first set bit at or after from
+func (b *Bitmap) NextSet(from uint) (uint, bool) {
+	i, _ := wordIndex(from)
+	if i >= len(b.words) {
+		return 0, false
+	}
+	// drop the bits below from in the first word
+	w := b.words[i] >> (from & wordMask)
+	if w != 0 {
+		return from + uint(bits.TrailingZeros64(w)), true
+	}
+	for i++; i < len(b.words); i++ {
+		if b.words[i] != 0 {
+			return uint(i)<<wordShift + uint(bits.TrailingZeros64(b.words[i])), true
+		}
 	}
+	return 0, false
 }
 
-func (b *bitMap) Set(num uint) {
-	if num > b.vmax {
-		b.vmax += 1024
-		if num > b.vmax {
-			b.vmax = num
+// NextClear returns the first clear bit at or after from. Bits beyond Len
+// are clear, so there always is one.
+func (b *Bitmap) NextClear(from uint) uint {
+	i, _ := wordIndex(from)
+	if i >= len(b.words) {
+		return from
+	}
+	w := ^b.words[i] >> (from & wordMask)
+	if w != 0 {
+		return from + uint(bits.TrailingZeros64(w))
+	}
+	for i++; i < len(b.words); i++ {
+		if b.words[i] != ^uint64(0) {
+			return uint(i)<<wordShift + uint(bits.TrailingZeros64(^b.words[i]))
 		}
-		inc := int(b.vmax+7)>>3 - len(b.graph)
-		if inc > 0 {
-			b.graph = append(b.graph, make([]byte, inc, inc)...)
+	}
+	return b.Len()
+}
+
+// All returns an iterator over the set bits in ascending order
+func (b *Bitmap) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				t := bits.TrailingZeros64(w)
+				if !yield(uint(i)<<wordShift + uint(t)) {
+					return
+				}
+				w &= w - 1
+			}
 		}
 	}
-	b.graph[num>>3] |= 1 << (num % 8)
 }
 
-func (b *bitMap) Del(num uint) {
-	if num <= b.vmax {
-		b.graph[num>>3] &^= 1 << (num % 8)
+// SetRange sets the bits in [start, end), growing the bitmap if needed
+func (b *Bitmap) SetRange(start, end uint) {
+	if start >= end {
+		return
 	}
+	b.grow(int((end - 1) >> wordShift))
+	b.applyRange(start, end, func(w *uint64, mask uint64) { *w |= mask })
 }
 
-func (b *bitMap) Check(num uint) bool {
-	if num > b.vmax {
-		return false
+// ClearRange clears the bits in [start, end)
+func (b *Bitmap) ClearRange(start, end uint) {
+	end = min(end, b.Len())
+	if start >= end {
+		return
+	}
+	b.applyRange(start, end, func(w *uint64, mask uint64) { *w &^= mask })
+}
+
+// applyRange calls fn with the mask of the bits in [start, end) for every
+// word it covers, whole words in the middle get a full mask
+func (b *Bitmap) applyRange(start, end uint, fn func(w *uint64, mask uint64)) {
+	first, last := int(start>>wordShift), int((end-1)>>wordShift)
+	firstMask := ^uint64(0) << (start & wordMask)
+	lastMask := ^uint64(0) >> (wordMask - (end-1)&wordMask)
+	if first == last {
+		fn(&b.words[first], firstMask&lastMask)
+		return
+	}
+	fn(&b.words[first], firstMask)
+	for i := first + 1; i < last; i++ {
+		fn(&b.words[i], ^uint64(0))
 	}
-	return b.graph[num>>3]&(1<<(num%8)) == 1
+	fn(&b.words[last], lastMask)
 }
@@ -2,7 +2,9 @@ package bitmap
 
 import (
 	"math"
+	"math/rand/v2"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -29,9 +31,15 @@ func BenchmarkBitMap_Del(b *testing.B) {
 	}
 }
 
+func BenchmarkBitMap_Count(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = bm.Count()
+	}
+}
+
 var (
 	defaultMax uint = 8192
-	size            = (defaultMax + 7) >> 3
+	size            = defaultMax/64 + 1
 )
 
 func TestNewBitMap(t *testing.T) {
@@ -41,14 +49,28 @@ func TestNewBitMap(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want *bitMap
+		want *Bitmap
 	}{
 		{
 			name: "all is ok",
 			args: args{
 				max: defaultMax,
 			},
-			want: &bitMap{vmax: defaultMax, graph: make([]byte, size, size)},
+			want: &Bitmap{words: make([]uint64, size)},
+		},
+		{
+			name: "default size",
+			args: args{
+				max: 0,
+			},
+			want: &Bitmap{words: make([]uint64, size)},
+		},
+		{
+			name: "word boundary",
+			args: args{
+				max: 63,
+			},
+			want: &Bitmap{words: make([]uint64, 1)},
 		},
 	}
 	for _, tt := range tests {
@@ -60,10 +82,9 @@ func TestNewBitMap(t *testing.T) {
 	}
 }
 
-func Test_bitMap_Check(t *testing.T) {
+func TestBitmap_Check(t *testing.T) {
 	type fields struct {
-		vmax  uint
-		graph []byte
+		words []uint64
 	}
 	type args struct {
 		num uint
@@ -77,8 +98,7 @@ func Test_bitMap_Check(t *testing.T) {
 		{
 			name: "all is ok",
 			fields: fields{
-				vmax:  defaultMax,
-				graph: make([]byte, size, size),
+				words: make([]uint64, size),
 			},
 			args: args{
 				num: 1024,
@@ -88,20 +108,38 @@ func Test_bitMap_Check(t *testing.T) {
 		{
 			name: "oversize",
 			fields: fields{
-				vmax:  defaultMax,
-				graph: make([]byte, size, size),
+				words: make([]uint64, size),
 			},
 			args: args{
-				num: 8193,
+				num: 8193 + 64,
 			},
 			want: false,
 		},
+		{
+			name: "bit other than the first of a byte",
+			fields: fields{
+				words: []uint64{1 << 9},
+			},
+			args: args{
+				num: 9,
+			},
+			want: true,
+		},
+		{
+			name: "last bit of a word",
+			fields: fields{
+				words: []uint64{1 << 63, 0},
+			},
+			args: args{
+				num: 63,
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &bitMap{
-				vmax:  tt.fields.vmax,
-				graph: tt.fields.graph,
+			b := &Bitmap{
+				words: tt.fields.words,
 			}
 			if got := b.Check(tt.args.num); got != tt.want {
 				t.Errorf("Check() = %v, want %v", got, tt.want)
@@ -110,10 +148,9 @@ func Test_bitMap_Check(t *testing.T) {
 	}
 }
 
-func Test_bitMap_Del(t *testing.T) {
+func TestBitmap_Del(t *testing.T) {
 	type fields struct {
-		vmax  uint
-		graph []byte
+		words []uint64
 	}
 	type args struct {
 		num uint
@@ -122,33 +159,45 @@ func Test_bitMap_Del(t *testing.T) {
 		name   string
 		fields fields
 		args   args
+		want   []uint64
 	}{
 		{
 			name: "all is ok",
 			fields: fields{
-				vmax:  defaultMax,
-				graph: make([]byte, size, size),
+				words: []uint64{0, 1<<3 | 1<<4},
+			},
+			args: args{
+				num: 64 + 3,
+			},
+			want: []uint64{0, 1 << 4},
+		},
+		{
+			name: "oversize",
+			fields: fields{
+				words: []uint64{1},
 			},
 			args: args{
 				num: 1024,
 			},
+			want: []uint64{1},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &bitMap{
-				vmax:  tt.fields.vmax,
-				graph: tt.fields.graph,
+			b := &Bitmap{
+				words: tt.fields.words,
 			}
 			b.Del(tt.args.num)
+			if !slices.Equal(b.words, tt.want) {
+				t.Errorf("Del() words = %b, want %b", b.words, tt.want)
+			}
 		})
 	}
 }
 
-func Test_bitMap_Set(t *testing.T) {
+func TestBitmap_Set(t *testing.T) {
 	type fields struct {
-		vmax  uint
-		graph []byte
+		words []uint64
 	}
 	type args struct {
 		num uint
@@ -157,35 +206,333 @@ func Test_bitMap_Set(t *testing.T) {
 		name   string
 		fields fields
 		args   args
+		want   []uint64
 	}{
 		{
 			name: "all is ok",
 			fields: fields{
-				vmax:  defaultMax,
-				graph: make([]byte, size, size),
+				words: make([]uint64, 2),
 			},
 			args: args{
-				num: 1024,
+				num: 70,
 			},
+			want: []uint64{0, 1 << 6},
 		},
 		{
 			name: "oversize",
 			fields: fields{
-				vmax:  defaultMax,
-				graph: make([]byte, size, size),
+				words: make([]uint64, 1),
 			},
 			args: args{
-				num: 9217,
+				num: 64*3 + 1,
 			},
+			want: []uint64{0, 0, 0, 1 << 1},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			b := &bitMap{
-				vmax:  tt.fields.vmax,
-				graph: tt.fields.graph,
+			b := &Bitmap{
+				words: tt.fields.words,
 			}
 			b.Set(tt.args.num)
+			if !slices.Equal(b.words, tt.want) {
+				t.Errorf("Set() words = %b, want %b", b.words, tt.want)
+			}
 		})
 	}
 }
+
+// boundaries are the values around byte and word edges
+var boundaries = []uint{0, 1, 7, 8, 9, 15, 16, 62, 63, 64, 65, 127, 128, 129, 191, 192, 255, 256}
+
+func TestBitmap_Boundaries(t *testing.T) {
+	for _, num := range boundaries {
+		b := NewBitMap(1)
+		b.Set(num)
+		for _, other := range boundaries {
+			if got := b.Test(other); got != (other == num) {
+				t.Errorf("after Set(%d), Test(%d) = %v", num, other, got)
+			}
+		}
+		if b.Count() != 1 {
+			t.Errorf("after Set(%d), Count() = %d", num, b.Count())
+		}
+		b.Flip(num)
+		if b.Test(num) || b.Count() != 0 {
+			t.Errorf("Flip(%d) did not clear the bit", num)
+		}
+		b.Flip(num)
+		if !b.Test(num) {
+			t.Errorf("Flip(%d) did not set the bit", num)
+		}
+		b.Clear(num)
+		if b.Test(num) {
+			t.Errorf("Clear(%d) did not clear the bit", num)
+		}
+	}
+}
+
+func TestBitmap_Grow(t *testing.T) {
+	b := NewBitMap(10)
+	if b.Len() != 64 {
+		t.Errorf("Len() = %d, want 64", b.Len())
+	}
+	b.Flip(1000)
+	if !b.Test(1000) || b.Len() != 1024 {
+		t.Errorf("Flip() beyond Len: Test() = %v, Len() = %d", b.Test(1000), b.Len())
+	}
+}
+
+func TestBitmap_Next(t *testing.T) {
+	b := NewBitMap(1)
+	for _, num := range []uint{3, 63, 64, 200} {
+		b.Set(num)
+	}
+	tests := []struct {
+		from      uint
+		nextSet   uint
+		found     bool
+		nextClear uint
+	}{
+		{from: 0, nextSet: 3, found: true, nextClear: 0},
+		{from: 3, nextSet: 3, found: true, nextClear: 4},
+		{from: 4, nextSet: 63, found: true, nextClear: 4},
+		{from: 63, nextSet: 63, found: true, nextClear: 65},
+		{from: 65, nextSet: 200, found: true, nextClear: 65},
+		{from: 201, found: false, nextClear: 201},
+		{from: 5000, found: false, nextClear: 5000},
+	}
+	for _, tt := range tests {
+		got, ok := b.NextSet(tt.from)
+		if ok != tt.found || (ok && got != tt.nextSet) {
+			t.Errorf("NextSet(%d) = %d, %v, want %d, %v", tt.from, got, ok, tt.nextSet, tt.found)
+		}
+		if got := b.NextClear(tt.from); got != tt.nextClear {
+			t.Errorf("NextClear(%d) = %d, want %d", tt.from, got, tt.nextClear)
+		}
+	}
+
+	full := NewBitMap(127)
+	full.SetRange(0, 128)
+	if got := full.NextClear(10); got != 128 {
+		t.Errorf("NextClear() of a full bitmap = %d, want 128", got)
+	}
+	full.Clear(100)
+	if got := full.NextClear(10); got != 100 {
+		t.Errorf("NextClear() = %d, want 100", got)
+	}
+}
+
+func TestBitmap_All(t *testing.T) {
+	b := NewBitMap(1)
+	want := []uint{0, 8, 63, 64, 130, 1000}
+	for _, num := range want {
+		b.Set(num)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	n := 0
+	for range b.All() {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("All() did not stop, n = %d", n)
+	}
+}
+
+func TestBitmap_Range(t *testing.T) {
+	ranges := [][2]uint{{0, 0}, {0, 1}, {3, 9}, {0, 64}, {60, 70}, {63, 65}, {1, 200}, {64, 128}, {100, 100}, {130, 129}}
+	for _, r := range ranges {
+		b := NewBitMap(1)
+		b.SetRange(r[0], r[1])
+		want := 0
+		if r[1] > r[0] {
+			want = int(r[1] - r[0])
+		}
+		if b.Count() != want {
+			t.Errorf("SetRange(%d, %d) set %d bits, want %d", r[0], r[1], b.Count(), want)
+		}
+		for i := uint(0); i < 256; i++ {
+			if b.Test(i) != (i >= r[0] && i < r[1]) {
+				t.Errorf("SetRange(%d, %d): Test(%d) = %v", r[0], r[1], i, b.Test(i))
+			}
+		}
+
+		b = NewBitMap(255)
+		b.SetRange(0, 256)
+		b.ClearRange(r[0], r[1])
+		if b.Count() != 256-want {
+			t.Errorf("ClearRange(%d, %d) left %d bits", r[0], r[1], b.Count())
+		}
+		for i := uint(0); i < 256; i++ {
+			if b.Test(i) == (i >= r[0] && i < r[1]) {
+				t.Errorf("ClearRange(%d, %d): Test(%d) = %v", r[0], r[1], i, b.Test(i))
+			}
+		}
+	}
+
+	b := NewBitMap(63)
+	b.ClearRange(10, 1000)
+	if b.Len() != 64 {
+		t.Errorf("ClearRange() beyond Len should not grow, Len() = %d", b.Len())
+	}
+}
+
+// millionBits returns two bitmaps of a million bits with about half of the
+// bits set in each
+func millionBits() (*Bitmap, *Bitmap) {
+	const n = 1 << 20
+	a, b := NewBitMap(n), NewBitMap(n)
+	for i := uint(0); i < n; i++ {
+		if i%2 == 0 {
+			a.Set(i)
+		}
+		if i%3 == 0 {
+			b.Set(i)
+		}
+	}
+	return a, b
+}
+
+func BenchmarkBitmap_And(b *testing.B) {
+	x, y := millionBits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.And(y)
+	}
+}
+
+func BenchmarkBitmap_Or(b *testing.B) {
+	x, y := millionBits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Or(y)
+	}
+}
+
+func BenchmarkBitmap_Xor(b *testing.B) {
+	x, y := millionBits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Xor(y)
+	}
+}
+
+func BenchmarkBitmap_AndNot(b *testing.B) {
+	x, y := millionBits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.AndNot(y)
+	}
+}
+
+func BenchmarkAnd(b *testing.B) {
+	x, y := millionBits()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = And(x, y)
+	}
+}
+
+func BenchmarkBitmap_Intersects(b *testing.B) {
+	x, _ := millionBits()
+	y := NewBitMap(1 << 20)
+	y.Set(1<<20 - 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = x.Intersects(y)
+	}
+}
+
+// sparseIDs returns n ids spread over [0, 1<<28), the dense bitmap needs
+// 32MB for that range whatever n is
+func sparseIDs(n int) []uint {
+	rng := rand.New(rand.NewPCG(7, 8))
+	ids := make([]uint, n)
+	for i := range ids {
+		ids[i] = uint(rng.Uint32N(1 << 28))
+	}
+	return ids
+}
+
+func BenchmarkSparse_BitmapSet(b *testing.B) {
+	ids := sparseIDs(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bm := NewBitMap(1)
+		for _, id := range ids {
+			bm.Set(id)
+		}
+		b.ReportMetric(float64(len(bm.words)*8), "bytes")
+	}
+}
+
+func BenchmarkSparse_RoaringSet(b *testing.B) {
+	ids := sparseIDs(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := NewRoaring()
+		for _, id := range ids {
+			r.Set(id)
+		}
+		b.ReportMetric(float64(r.SizeInBytes()), "bytes")
+	}
+}
+
+func BenchmarkSparse_BitmapCheck(b *testing.B) {
+	ids := sparseIDs(100000)
+	bm := NewBitMap(1)
+	for _, id := range ids {
+		bm.Set(id)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bm.Check(ids[i%len(ids)])
+	}
+}
+
+func BenchmarkSparse_RoaringCheck(b *testing.B) {
+	ids := sparseIDs(100000)
+	r := NewRoaring()
+	for _, id := range ids {
+		r.Set(id)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = r.Check(ids[i%len(ids)])
+	}
+}
+
+func BenchmarkSparse_BitmapAnd(b *testing.B) {
+	x, y := NewBitMap(1), NewBitMap(1)
+	for i, id := range sparseIDs(200000) {
+		if i%2 == 0 {
+			x.Set(id)
+		} else {
+			y.Set(id)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = And(x, y)
+	}
+}
+
+func BenchmarkSparse_RoaringAnd(b *testing.B) {
+	x, y := NewRoaring(), NewRoaring()
+	for i, id := range sparseIDs(200000) {
+		if i%2 == 0 {
+			x.Set(id)
+		} else {
+			y.Set(id)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = RoaringAnd(x, y)
+	}
+}
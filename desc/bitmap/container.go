@@ -0,0 +1,392 @@
+package bitmap
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// A Roaring bitmap splits every value into its high 16 bits, which select a
+// container, and its low 16 bits, which are stored in that container in one
+// of three representations:
+//   - arrayContainer, a sorted slice, for up to arrayMaxSize values
+//   - bitmapContainer, a plain 65536-bit bitmap, for denser containers
+//   - runContainer, a list of runs of consecutive values, produced by
+//     Roaring.RunOptimize when it is the most compact form and given up as
+//     soon as edits make another form smaller
+const (
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / wordBits
+	// bitmapBytes is the serialized size of a bitmap container
+	bitmapBytes = 8 * bitmapWords
+)
+
+type container interface {
+	// add and remove return the container to use afterwards, which is a new
+	// one when the representation changes
+	add(x uint16) container
+	remove(x uint16) container
+	contains(x uint16) bool
+	cardinality() int
+	// iterate calls yield for every value in ascending order and reports
+	// whether it ran to completion
+	iterate(yield func(uint16) bool) bool
+	clone() container
+	toBitmap() *bitmapContainer
+}
+
+type arrayContainer []uint16
+
+func (a arrayContainer) add(x uint16) container {
+	i, found := slices.BinarySearch(a, x)
+	if found {
+		return a
+	}
+	if len(a) >= arrayMaxSize {
+		return a.toBitmap().add(x)
+	}
+	return arrayContainer(slices.Insert(a, i, x))
+}
+
+func (a arrayContainer) remove(x uint16) container {
+	i, found := slices.BinarySearch(a, x)
+	if !found {
+		return a
+	}
+	return arrayContainer(slices.Delete(a, i, i+1))
+}
+
+func (a arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(a, x)
+	return found
+}
+
+func (a arrayContainer) cardinality() int {
+	return len(a)
+}
+
+func (a arrayContainer) iterate(yield func(uint16) bool) bool {
+	for _, x := range a {
+		if !yield(x) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a arrayContainer) clone() container {
+	return slices.Clone(a)
+}
+
+func (a arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{card: len(a)}
+	for _, x := range a {
+		b.words[x>>wordShift] |= 1 << (x & wordMask)
+	}
+	return b
+}
+
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	mask := uint64(1) << (x & wordMask)
+	if w := &b.words[x>>wordShift]; *w&mask == 0 {
+		*w |= mask
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	mask := uint64(1) << (x & wordMask)
+	if w := &b.words[x>>wordShift]; *w&mask != 0 {
+		*w &^= mask
+		b.card--
+	}
+	if b.card <= arrayMaxSize {
+		return b.toArray()
+	}
+	return b
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x>>wordShift]&(1<<(x&wordMask)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) iterate(yield func(uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			if !yield(uint16(i<<wordShift + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) clone() container {
+	c := *b
+	return &c
+}
+
+func (b *bitmapContainer) toBitmap() *bitmapContainer {
+	return b
+}
+
+func (b *bitmapContainer) toArray() arrayContainer {
+	a := make(arrayContainer, 0, b.card)
+	b.iterate(func(x uint16) bool {
+		a = append(a, x)
+		return true
+	})
+	return a
+}
+
+// normalize recomputes the cardinality after a word level operation and
+// switches to an array when the container became sparse
+func (b *bitmapContainer) normalize() container {
+	b.card = 0
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+	if b.card <= arrayMaxSize {
+		return b.toArray()
+	}
+	return b
+}
+
+// run is the inclusive interval [start, last]
+type run struct {
+	start, last uint16
+}
+
+type runContainer []run
+
+// find returns the index of the run holding x, or the index where a run
+// starting at x would be inserted
+func (r runContainer) find(x uint16) (int, bool) {
+	i, _ := slices.BinarySearchFunc(r, x, func(e run, x uint16) int {
+		switch {
+		case e.last < x:
+			return -1
+		case e.start > x:
+			return 1
+		}
+		return 0
+	})
+	return i, i < len(r) && r[i].start <= x && x <= r[i].last
+}
+
+func (r runContainer) add(x uint16) container {
+	i, found := r.find(x)
+	if found {
+		return r
+	}
+	joinPrev := i > 0 && r[i-1].last+1 == x
+	joinNext := i < len(r) && r[i].start-1 == x
+	switch {
+	case joinPrev && joinNext:
+		r[i-1].last = r[i].last
+		return slices.Delete(r, i, i+1)
+	case joinPrev:
+		r[i-1].last = x
+	case joinNext:
+		r[i].start = x
+	default:
+		return runContainer(slices.Insert(r, i, run{start: x, last: x})).shrink()
+	}
+	return r
+}
+
+func (r runContainer) remove(x uint16) container {
+	i, found := r.find(x)
+	if !found {
+		return r
+	}
+	switch e := r[i]; {
+	case e.start == e.last:
+		return runContainer(slices.Delete(r, i, i+1)).shrink()
+	case e.start == x:
+		r[i].start++
+	case e.last == x:
+		r[i].last--
+	default:
+		r[i].last = x - 1
+		return runContainer(slices.Insert(r, i+1, run{start: x + 1, last: e.last})).shrink()
+	}
+	return r.shrink()
+}
+
+// shrink switches to an array or a bitmap container once the runs are no
+// longer the smallest form. Only edits that add a run or remove values can
+// make them lose, and optimize picks runs only when strictly smaller, so a
+// container does not flip back and forth.
+func (r runContainer) shrink() container {
+	card := r.cardinality()
+	if 2+4*len(r) <= min(2*card, bitmapBytes) {
+		return r
+	}
+	if card <= arrayMaxSize {
+		a := make(arrayContainer, 0, card)
+		r.iterate(func(x uint16) bool {
+			a = append(a, x)
+			return true
+		})
+		return a
+	}
+	return r.toBitmap()
+}
+
+func (r runContainer) contains(x uint16) bool {
+	_, found := r.find(x)
+	return found
+}
+
+func (r runContainer) cardinality() int {
+	n := 0
+	for _, e := range r {
+		n += int(e.last-e.start) + 1
+	}
+	return n
+}
+
+func (r runContainer) iterate(yield func(uint16) bool) bool {
+	for _, e := range r {
+		for x := int(e.start); x <= int(e.last); x++ {
+			if !yield(uint16(x)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r runContainer) clone() container {
+	return slices.Clone(r)
+}
+
+func (r runContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, e := range r {
+		for x := int(e.start); x <= int(e.last); x++ {
+			b.words[x>>wordShift] |= 1 << (x & wordMask)
+		}
+		b.card += int(e.last-e.start) + 1
+	}
+	return b
+}
+
+// toRuns returns the runs of c
+func toRuns(c container) runContainer {
+	var r runContainer
+	c.iterate(func(x uint16) bool {
+		if n := len(r); n > 0 && int(r[n-1].last)+1 == int(x) {
+			r[n-1].last = x
+		} else {
+			r = append(r, run{start: x, last: x})
+		}
+		return true
+	})
+	return r
+}
+
+// optimize returns the most compact representation of c, comparing the
+// serialized sizes of the three forms
+func optimize(c container) container {
+	card := c.cardinality()
+	runs := toRuns(c)
+	runSize := 2 + 4*len(runs)
+	arraySize := 2 * card
+	switch {
+	case runSize < min(arraySize, bitmapBytes):
+		return runs
+	case card <= arrayMaxSize:
+		if a, ok := c.(arrayContainer); ok {
+			return a
+		}
+		return c.toBitmap().toArray()
+	default:
+		return c.toBitmap()
+	}
+}
+
+type containerOp int
+
+const (
+	opAnd containerOp = iota
+	opOr
+	opXor
+	opAndNot
+)
+
+// combine applies op to a and b and returns the resulting container, which
+// may be empty. Arrays are merged directly, every other combination goes
+// through the bitmap form.
+func combine(a, b container, op containerOp) container {
+	if x, ok := a.(arrayContainer); ok {
+		if y, ok := b.(arrayContainer); ok {
+			res := mergeArrays(x, y, op)
+			if len(res) > arrayMaxSize {
+				return res.toBitmap()
+			}
+			return res
+		}
+	}
+	res := a.toBitmap()
+	if bc, ok := a.(*bitmapContainer); ok {
+		res = bc.clone().(*bitmapContainer)
+	}
+	other := b.toBitmap()
+	for i := range res.words {
+		switch op {
+		case opAnd:
+			res.words[i] &= other.words[i]
+		case opOr:
+			res.words[i] |= other.words[i]
+		case opXor:
+			res.words[i] ^= other.words[i]
+		case opAndNot:
+			res.words[i] &^= other.words[i]
+		}
+	}
+	return res.normalize()
+}
+
+func mergeArrays(a, b arrayContainer, op containerOp) arrayContainer {
+	res := make(arrayContainer, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			if op != opAnd {
+				res = append(res, a[i])
+			}
+			i++
+		case a[i] > b[j]:
+			if op == opOr || op == opXor {
+				res = append(res, b[j])
+			}
+			j++
+		default:
+			if op == opAnd || op == opOr {
+				res = append(res, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if op != opAnd {
+		res = append(res, a[i:]...)
+	}
+	if op == opOr || op == opXor {
+		res = append(res, b[j:]...)
+	}
+	return res
+}
@@ -0,0 +1,141 @@
+package bitmap
+
+// Operations between bitmaps of different lengths treat the missing words of
+// the shorter operand as zero.
+
+// Clone returns a copy of b
+func (b *Bitmap) Clone() *Bitmap {
+	return &Bitmap{words: append([]uint64(nil), b.words...)}
+}
+
+// And keeps only the bits that are also set in other
+func (b *Bitmap) And(other *Bitmap) {
+	n := min(len(b.words), len(other.words))
+	for i := 0; i < n; i++ {
+		b.words[i] &= other.words[i]
+	}
+	clear(b.words[n:])
+}
+
+// Or sets the bits that are set in other, growing b if needed
+func (b *Bitmap) Or(other *Bitmap) {
+	b.grow(len(other.words) - 1)
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+}
+
+// Xor toggles the bits that are set in other, growing b if needed
+func (b *Bitmap) Xor(other *Bitmap) {
+	b.grow(len(other.words) - 1)
+	for i, w := range other.words {
+		b.words[i] ^= w
+	}
+}
+
+// AndNot clears the bits that are set in other
+func (b *Bitmap) AndNot(other *Bitmap) {
+	n := min(len(b.words), len(other.words))
+	for i := 0; i < n; i++ {
+		b.words[i] &^= other.words[i]
+	}
+}
+
+// Not toggles the bits in [0, length), growing b if needed
+func (b *Bitmap) Not(length uint) {
+	if length == 0 {
+		return
+	}
+	b.grow(int((length - 1) >> wordShift))
+	b.applyRange(0, length, func(w *uint64, mask uint64) { *w ^= mask })
+}
+
+// Equal reports whether b and other have the same bits set
+func (b *Bitmap) Equal(other *Bitmap) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset reports whether every bit set in b is also set in other
+func (b *Bitmap) IsSubset(other *Bitmap) bool {
+	for i, w := range b.words {
+		var o uint64
+		if i < len(other.words) {
+			o = other.words[i]
+		}
+		if w&^o != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Intersects reports whether b and other have at least one bit set in common
+func (b *Bitmap) Intersects(other *Bitmap) bool {
+	n := min(len(b.words), len(other.words))
+	for i := 0; i < n; i++ {
+		if b.words[i]&other.words[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// And returns a new bitmap holding the bits set in both a and b
+func And(a, b *Bitmap) *Bitmap {
+	if len(a.words) > len(b.words) {
+		a, b = b, a
+	}
+	res := a.Clone()
+	res.And(b)
+	return res
+}
+
+// Or returns a new bitmap holding the bits set in a or b
+func Or(a, b *Bitmap) *Bitmap {
+	if len(a.words) < len(b.words) {
+		a, b = b, a
+	}
+	res := a.Clone()
+	res.Or(b)
+	return res
+}
+
+// Xor returns a new bitmap holding the bits set in exactly one of a and b
+func Xor(a, b *Bitmap) *Bitmap {
+	if len(a.words) < len(b.words) {
+		a, b = b, a
+	}
+	res := a.Clone()
+	res.Xor(b)
+	return res
+}
+
+// AndNot returns a new bitmap holding the bits set in a but not in b
+func AndNot(a, b *Bitmap) *Bitmap {
+	res := a.Clone()
+	res.AndNot(b)
+	return res
+}
+
+// Not returns a new bitmap holding the bits in [0, length) that are clear in
+// b; the bits of b from length on are dropped
+func Not(b *Bitmap, length uint) *Bitmap {
+	res := b.Clone()
+	res.Not(length)
+	res.ClearRange(length, res.Len())
+	return res
+}
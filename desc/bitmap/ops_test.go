@@ -0,0 +1,113 @@
+package bitmap
+
+import (
+	"slices"
+	"testing"
+)
+
+func bitmapOf(nums ...uint) *Bitmap {
+	b := NewBitMap(1)
+	for _, num := range nums {
+		b.Set(num)
+	}
+	return b
+}
+
+func TestBitmap_Operations(t *testing.T) {
+	// a is longer than b, so both orders exercise the length handling
+	a := bitmapOf(1, 63, 64, 200, 500)
+	b := bitmapOf(1, 2, 64, 100)
+
+	tests := []struct {
+		name    string
+		inPlace func(x, y *Bitmap)
+		alloc   func(x, y *Bitmap) *Bitmap
+		ab, ba  []uint
+	}{
+		{name: "and", inPlace: (*Bitmap).And, alloc: And, ab: []uint{1, 64}, ba: []uint{1, 64}},
+		{name: "or", inPlace: (*Bitmap).Or, alloc: Or, ab: []uint{1, 2, 63, 64, 100, 200, 500}, ba: []uint{1, 2, 63, 64, 100, 200, 500}},
+		{name: "xor", inPlace: (*Bitmap).Xor, alloc: Xor, ab: []uint{2, 63, 100, 200, 500}, ba: []uint{2, 63, 100, 200, 500}},
+		{name: "and not", inPlace: (*Bitmap).AndNot, alloc: AndNot, ab: []uint{63, 200, 500}, ba: []uint{2, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slices.Collect(tt.alloc(a, b).All()); !slices.Equal(got, tt.ab) {
+				t.Errorf("f(a, b) = %v, want %v", got, tt.ab)
+			}
+			if got := slices.Collect(tt.alloc(b, a).All()); !slices.Equal(got, tt.ba) {
+				t.Errorf("f(b, a) = %v, want %v", got, tt.ba)
+			}
+
+			x := a.Clone()
+			tt.inPlace(x, b)
+			if got := slices.Collect(x.All()); !slices.Equal(got, tt.ab) {
+				t.Errorf("a.f(b) = %v, want %v", got, tt.ab)
+			}
+			y := b.Clone()
+			tt.inPlace(y, a)
+			if got := slices.Collect(y.All()); !slices.Equal(got, tt.ba) {
+				t.Errorf("b.f(a) = %v, want %v", got, tt.ba)
+			}
+		})
+	}
+	if got := slices.Collect(a.All()); !slices.Equal(got, []uint{1, 63, 64, 200, 500}) {
+		t.Errorf("operations modified their operand: %v", got)
+	}
+}
+
+func TestBitmap_Not(t *testing.T) {
+	b := bitmapOf(0, 5, 64)
+	got := Not(b, 66)
+	if got.Count() != 63 || got.Test(0) || got.Test(5) || got.Test(64) || !got.Test(65) || got.Test(66) {
+		t.Errorf("Not(b, 66) = %v", slices.Collect(got.All()))
+	}
+	if b.Count() != 3 {
+		t.Error("Not() modified its operand")
+	}
+	// bits of b beyond length are not part of the result
+	got = Not(bitmapOf(3, 100), 10)
+	if want := []uint{0, 1, 2, 4, 5, 6, 7, 8, 9}; !slices.Equal(slices.Collect(got.All()), want) {
+		t.Errorf("Not({3, 100}, 10) = %v, want %v", slices.Collect(got.All()), want)
+	}
+	if got = Not(bitmapOf(3, 100), 0); got.Count() != 0 {
+		t.Errorf("Not({3, 100}, 0) = %v, want nothing", slices.Collect(got.All()))
+	}
+
+	b.Not(200)
+	if b.Count() != 197 || !b.Test(199) || b.Test(200) {
+		t.Errorf("Not(200) set %d bits", b.Count())
+	}
+	b.Not(0)
+	if b.Count() != 197 {
+		t.Error("Not(0) should be a no-op")
+	}
+}
+
+func TestBitmap_Relations(t *testing.T) {
+	small := bitmapOf(3, 64)
+	large := NewBitMap(10000)
+	large.Set(3)
+	large.Set(64)
+
+	if !small.Equal(large) || !large.Equal(small) {
+		t.Error("bitmaps with the same bits but different lengths should be equal")
+	}
+	large.Set(9000)
+	if small.Equal(large) || large.Equal(small) {
+		t.Error("bitmaps with different bits should not be equal")
+	}
+	if !small.Equal(small.Clone()) || bitmapOf(1).Equal(bitmapOf(2)) {
+		t.Error("Equal() returned a wrong result")
+	}
+
+	if !small.IsSubset(large) || large.IsSubset(small) {
+		t.Error("IsSubset() returned a wrong result")
+	}
+	if !NewBitMap(1).IsSubset(small) || bitmapOf(3, 65).IsSubset(small) {
+		t.Error("IsSubset() returned a wrong result")
+	}
+
+	if !small.Intersects(large) || small.Intersects(bitmapOf(4, 9000)) || NewBitMap(1).Intersects(large) {
+		t.Error("Intersects() returned a wrong result")
+	}
+}
@@ -0,0 +1,245 @@
+package bitmap
+
+import (
+	"iter"
+	"math"
+	"slices"
+)
+
+// BitSet is the common interface of the dense Bitmap, the compressed
+// Roaring bitmap and the concurrent AtomicBitmap
+type BitSet interface {
+	Set(num uint)
+	Del(num uint)
+	Check(num uint) bool
+	Count() int
+	All() iter.Seq[uint]
+}
+
+// Roaring is a compressed bitmap of 32-bit values. Values are grouped by
+// their high 16 bits into containers that are stored as sorted arrays, plain
+// bitmaps or runs depending on their density, so sparse sets spread over
+// the whole 32-bit space stay small. A Roaring is not safe for concurrent use.
+type Roaring struct {
+	// keys holds the high 16 bits of each container in ascending order
+	keys       []uint16
+	containers []container
+}
+
+// NewRoaring creates an empty Roaring bitmap
+func NewRoaring() *Roaring {
+	return &Roaring{}
+}
+
+func split32(num uint32) (uint16, uint16) {
+	return uint16(num >> 16), uint16(num)
+}
+
+func (r *Roaring) find(key uint16) (int, bool) {
+	return slices.BinarySearch(r.keys, key)
+}
+
+// Set adds num to the bitmap. It panics if num does not fit in 32 bits.
+func (r *Roaring) Set(num uint) {
+	if num > math.MaxUint32 {
+		panic("bitmap: Roaring value out of 32-bit range")
+	}
+	key, low := split32(uint32(num))
+	i, found := r.find(key)
+	if !found {
+		r.keys = slices.Insert(r.keys, i, key)
+		r.containers = slices.Insert(r.containers, i, container(arrayContainer{low}))
+		return
+	}
+	r.containers[i] = r.containers[i].add(low)
+}
+
+// Del removes num from the bitmap
+func (r *Roaring) Del(num uint) {
+	if num > math.MaxUint32 {
+		return
+	}
+	key, low := split32(uint32(num))
+	i, found := r.find(key)
+	if !found {
+		return
+	}
+	r.containers[i] = r.containers[i].remove(low)
+	if r.containers[i].cardinality() == 0 {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.containers = slices.Delete(r.containers, i, i+1)
+	}
+}
+
+// Check reports whether num is in the bitmap
+func (r *Roaring) Check(num uint) bool {
+	if num > math.MaxUint32 {
+		return false
+	}
+	key, low := split32(uint32(num))
+	i, found := r.find(key)
+	return found && r.containers[i].contains(low)
+}
+
+// Count returns the number of values in the bitmap
+func (r *Roaring) Count() int {
+	n := 0
+	for _, c := range r.containers {
+		n += c.cardinality()
+	}
+	return n
+}
+
+// All returns an iterator over the values in ascending order
+func (r *Roaring) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, c := range r.containers {
+			high := uint(r.keys[i]) << 16
+			if !c.iterate(func(low uint16) bool { return yield(high | uint(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of r
+func (r *Roaring) Clone() *Roaring {
+	c := &Roaring{
+		keys:       slices.Clone(r.keys),
+		containers: make([]container, len(r.containers)),
+	}
+	for i, ct := range r.containers {
+		c.containers[i] = ct.clone()
+	}
+	return c
+}
+
+// RunOptimize converts every container to its most compact representation,
+// which is worthwhile after adding long sequences of consecutive values
+func (r *Roaring) RunOptimize() {
+	for i, c := range r.containers {
+		r.containers[i] = optimize(c)
+	}
+}
+
+// SizeInBytes estimates the memory used by the containers
+func (r *Roaring) SizeInBytes() int {
+	n := 2 * len(r.keys)
+	for _, c := range r.containers {
+		switch c := c.(type) {
+		case arrayContainer:
+			n += 2 * len(c)
+		case runContainer:
+			n += 4 * len(c)
+		default:
+			n += bitmapBytes
+		}
+	}
+	return n
+}
+
+// And keeps only the values that are also in other
+func (r *Roaring) And(other *Roaring) {
+	r.combine(other, opAnd)
+}
+
+// Or adds the values of other
+func (r *Roaring) Or(other *Roaring) {
+	r.combine(other, opOr)
+}
+
+// Xor keeps the values that are in exactly one of r and other
+func (r *Roaring) Xor(other *Roaring) {
+	r.combine(other, opXor)
+}
+
+// AndNot removes the values of other
+func (r *Roaring) AndNot(other *Roaring) {
+	r.combine(other, opAndNot)
+}
+
+// Equal reports whether r and other hold the same values
+func (r *Roaring) Equal(other *Roaring) bool {
+	if !slices.Equal(r.keys, other.keys) {
+		return false
+	}
+	for i, c := range r.containers {
+		if c.cardinality() != other.containers[i].cardinality() ||
+			combine(c, other.containers[i], opXor).cardinality() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// combine merges the sorted key lists of r and other and replaces the
+// containers of r with the result of op
+func (r *Roaring) combine(other *Roaring, op containerOp) {
+	keys := make([]uint16, 0, len(r.keys)+len(other.keys))
+	containers := make([]container, 0, len(r.keys)+len(other.keys))
+	appendNonEmpty := func(key uint16, c container) {
+		if c.cardinality() > 0 {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(r.keys) && j < len(other.keys) {
+		switch {
+		case r.keys[i] < other.keys[j]:
+			if op != opAnd {
+				appendNonEmpty(r.keys[i], r.containers[i])
+			}
+			i++
+		case r.keys[i] > other.keys[j]:
+			if op == opOr || op == opXor {
+				appendNonEmpty(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			appendNonEmpty(r.keys[i], combine(r.containers[i], other.containers[j], op))
+			i++
+			j++
+		}
+	}
+	if op != opAnd {
+		for ; i < len(r.keys); i++ {
+			appendNonEmpty(r.keys[i], r.containers[i])
+		}
+	}
+	if op == opOr || op == opXor {
+		for ; j < len(other.keys); j++ {
+			appendNonEmpty(other.keys[j], other.containers[j].clone())
+		}
+	}
+	r.keys, r.containers = keys, containers
+}
+
+// RoaringAnd returns a new bitmap holding the values in both a and b
+func RoaringAnd(a, b *Roaring) *Roaring {
+	res := a.Clone()
+	res.And(b)
+	return res
+}
+
+// RoaringOr returns a new bitmap holding the values in a or b
+func RoaringOr(a, b *Roaring) *Roaring {
+	res := a.Clone()
+	res.Or(b)
+	return res
+}
+
+// RoaringXor returns a new bitmap holding the values in exactly one of a and b
+func RoaringXor(a, b *Roaring) *Roaring {
+	res := a.Clone()
+	res.Xor(b)
+	return res
+}
+
+// RoaringAndNot returns a new bitmap holding the values in a but not in b
+func RoaringAndNot(a, b *Roaring) *Roaring {
+	res := a.Clone()
+	res.AndNot(b)
+	return res
+}
@@ -0,0 +1,356 @@
+package bitmap
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// randomValues returns n values in [0, limit) where about a quarter of the
+// values fall into a handful of dense clusters, so that every container
+// representation is exercised
+func randomValues(rng *rand.Rand, n int, limit uint32) []uint {
+	vals := make([]uint, n)
+	for i := range vals {
+		if i%4 == 0 {
+			vals[i] = uint(rng.Uint32N(4)<<16 | rng.Uint32N(1<<16))
+		} else {
+			vals[i] = uint(rng.Uint32N(limit))
+		}
+	}
+	return vals
+}
+
+func TestRoaring_MatchesBitmap(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	r := NewRoaring()
+	dense := NewBitMap(1)
+	for i, v := range randomValues(rng, 60000, 1<<22) {
+		if i%5 == 4 {
+			r.Del(v)
+			dense.Del(v)
+		} else {
+			r.Set(v)
+			dense.Set(v)
+		}
+		if i%10000 == 0 {
+			r.RunOptimize()
+		}
+	}
+	if got, want := slices.Collect(r.All()), slices.Collect(dense.All()); !slices.Equal(got, want) {
+		t.Fatalf("Roaring and Bitmap diverged: %d vs %d values", len(got), len(want))
+	}
+	if r.Count() != dense.Count() {
+		t.Errorf("Count() = %d, want %d", r.Count(), dense.Count())
+	}
+	for i := 0; i < 1000; i++ {
+		v := uint(rng.Uint32N(1 << 22))
+		if r.Check(v) != dense.Check(v) {
+			t.Errorf("Check(%d) = %v, want %v", v, r.Check(v), dense.Check(v))
+		}
+	}
+}
+
+func TestRoaring_Containers(t *testing.T) {
+	r := NewRoaring()
+	for i := uint(0); i < arrayMaxSize; i++ {
+		r.Set(i * 2)
+	}
+	if _, ok := r.containers[0].(arrayContainer); !ok {
+		t.Fatalf("%d values should fit an array container", arrayMaxSize)
+	}
+	r.Set(1)
+	if _, ok := r.containers[0].(*bitmapContainer); !ok {
+		t.Fatal("container should switch to a bitmap above the array limit")
+	}
+	if !r.Check(1) || !r.Check(2) || r.Check(3) {
+		t.Error("bitmap container lookups returned a wrong result")
+	}
+	r.Del(3)
+	r.Del(1)
+	if _, ok := r.containers[0].(arrayContainer); !ok {
+		t.Fatal("container should switch back to an array")
+	}
+
+	r = NewRoaring()
+	for i := uint(100); i < 50000; i++ {
+		r.Set(i)
+	}
+	before := r.SizeInBytes()
+	r.RunOptimize()
+	rc, ok := r.containers[0].(runContainer)
+	if !ok || len(rc) != 1 {
+		t.Fatalf("a single sequence should become one run, got %T", r.containers[0])
+	}
+	if after := r.SizeInBytes(); after >= before {
+		t.Errorf("RunOptimize() grew the bitmap from %d to %d bytes", before, after)
+	}
+	if r.Count() != 49900 || !r.Check(100) || !r.Check(49999) || r.Check(99) || r.Check(50000) {
+		t.Error("run container lost values")
+	}
+
+	// edit the run container in every possible way
+	r.Set(99)    // extend the start
+	r.Set(50000) // extend the end
+	r.Set(50002) // new run
+	r.Set(50001) // join two runs
+	r.Set(200)   // already present
+	r.Del(1000)  // split
+	r.Del(99)    // shrink the start
+	r.Del(50002) // shrink the end
+	r.Del(1000)  // absent
+	r.Set(60000) // single value run
+	r.Del(60000) // remove a single value run
+	want := 49900 + 1
+	if r.Count() != want || r.Check(1000) || !r.Check(999) || !r.Check(1001) || !r.Check(50001) {
+		t.Errorf("run edits: Count() = %d, want %d", r.Count(), want)
+	}
+
+	// a sparse container should be optimized to an array
+	r = NewRoaring()
+	for i := uint(0); i < 100; i++ {
+		r.Set(i * 7)
+	}
+	r.RunOptimize()
+	if _, ok := r.containers[0].(arrayContainer); !ok {
+		t.Errorf("sparse container optimized to %T", r.containers[0])
+	}
+	// and a noisy dense one to a bitmap
+	r = NewRoaring()
+	for i := uint(0); i < 1<<16; i += 2 {
+		r.Set(i)
+	}
+	r.RunOptimize()
+	if _, ok := r.containers[0].(*bitmapContainer); !ok {
+		t.Errorf("dense container optimized to %T", r.containers[0])
+	}
+}
+
+func TestRoaring_RunContainerShrinks(t *testing.T) {
+	r := NewRoaring()
+	for i := uint(0); i < 100; i++ {
+		r.Set(i)
+	}
+	r.RunOptimize()
+	if _, ok := r.containers[0].(runContainer); !ok {
+		t.Fatalf("a single sequence optimized to %T", r.containers[0])
+	}
+	// isolated values make the runs grow past an array of the same values
+	for i := uint(200); i < 600; i += 2 {
+		r.Set(i)
+	}
+	if _, ok := r.containers[0].(arrayContainer); !ok {
+		t.Fatalf("sparse edits left a %T", r.containers[0])
+	}
+
+	r = NewRoaring()
+	for i := uint(0); i < 100; i++ {
+		r.Set(i)
+	}
+	r.RunOptimize()
+	for i := uint(200); i < 1<<16; i += 2 {
+		r.Set(i)
+	}
+	if _, ok := r.containers[0].(*bitmapContainer); !ok {
+		t.Fatalf("dense alternating values left a %T", r.containers[0])
+	}
+	if size := r.SizeInBytes(); size > 2+bitmapBytes {
+		t.Errorf("SizeInBytes() = %d, want at most %d", size, 2+bitmapBytes)
+	}
+	if r.Count() != 100+(1<<16-200)/2 || !r.Check(99) || !r.Check(65534) || r.Check(201) {
+		t.Error("converting the run container lost values")
+	}
+
+	// splitting runs by removing values works the same way
+	r = NewRoaring()
+	for i := uint(0); i < 1000; i++ {
+		r.Set(i)
+	}
+	r.RunOptimize()
+	for i := uint(1); i < 1000; i += 3 {
+		r.Del(i)
+	}
+	if _, ok := r.containers[0].(arrayContainer); !ok {
+		t.Fatalf("removals left a %T", r.containers[0])
+	}
+	if r.Count() != 1000-333 || r.Check(1) || !r.Check(2) {
+		t.Error("converting the run container lost values")
+	}
+}
+
+func TestRoaring_Operations(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	build := func(vals []uint) (*Roaring, *Bitmap) {
+		r, b := NewRoaring(), NewBitMap(1)
+		for _, v := range vals {
+			r.Set(v)
+			b.Set(v)
+		}
+		return r, b
+	}
+	ra, da := build(randomValues(rng, 30000, 1<<21))
+	rb, db := build(randomValues(rng, 30000, 1<<21))
+	// run containers take part as well
+	rb.RunOptimize()
+	for i := uint(1 << 20); i < 1<<20+20000; i++ {
+		ra.Set(i)
+		da.Set(i)
+	}
+	ra.RunOptimize()
+
+	tests := []struct {
+		name  string
+		rfn   func(a, b *Roaring) *Roaring
+		dense func(a, b *Bitmap) *Bitmap
+	}{
+		{name: "and", rfn: RoaringAnd, dense: And},
+		{name: "or", rfn: RoaringOr, dense: Or},
+		{name: "xor", rfn: RoaringXor, dense: Xor},
+		{name: "and not", rfn: RoaringAndNot, dense: AndNot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, ops := range [][2]int{{0, 1}, {1, 0}} {
+				r := []*Roaring{ra, rb}
+				d := []*Bitmap{da, db}
+				got := slices.Collect(tt.rfn(r[ops[0]], r[ops[1]]).All())
+				want := slices.Collect(tt.dense(d[ops[0]], d[ops[1]]).All())
+				if !slices.Equal(got, want) {
+					t.Errorf("operands %v: %d values, want %d", ops, len(got), len(want))
+				}
+			}
+		})
+	}
+	if got := slices.Collect(ra.All()); !slices.Equal(got, slices.Collect(da.All())) {
+		t.Error("operations modified their operand")
+	}
+
+	// containers present in only one operand
+	p, q := NewRoaring(), NewRoaring()
+	for _, v := range []uint{1, 1 << 20, 3 << 20} {
+		p.Set(v)
+	}
+	for _, v := range []uint{1 << 17, 1<<20 + 5, 5 << 20} {
+		q.Set(v)
+	}
+	for _, tt := range tests {
+		for _, ops := range [][2]*Roaring{{p, q}, {q, p}} {
+			want := tt.dense(bitmapFrom(ops[0]), bitmapFrom(ops[1]))
+			if got := slices.Collect(tt.rfn(ops[0], ops[1]).All()); !slices.Equal(got, slices.Collect(want.All())) {
+				t.Errorf("%s of disjoint containers = %v", tt.name, got)
+			}
+		}
+	}
+
+	// small arrays that merge into a bitmap
+	x, y := NewRoaring(), NewRoaring()
+	for i := uint(0); i < arrayMaxSize; i++ {
+		x.Set(2 * i)
+		y.Set(2*i + 1)
+	}
+	x.Or(y)
+	if _, ok := x.containers[0].(*bitmapContainer); !ok || x.Count() != 2*arrayMaxSize {
+		t.Errorf("Or() of two full arrays = %T with %d values", x.containers[0], x.Count())
+	}
+	x.AndNot(y)
+	if _, ok := x.containers[0].(arrayContainer); !ok || x.Count() != arrayMaxSize {
+		t.Errorf("AndNot() = %T with %d values", x.containers[0], x.Count())
+	}
+	x.And(y)
+	if x.Count() != 0 || len(x.keys) != 0 {
+		t.Error("And() of disjoint bitmaps should drop every container")
+	}
+}
+
+func TestRoaring_Equal(t *testing.T) {
+	a, b := NewRoaring(), NewRoaring()
+	for i := uint(0); i < 10000; i++ {
+		a.Set(i)
+		b.Set(i)
+	}
+	b.RunOptimize()
+	if !a.Equal(b) || !b.Equal(a.Clone()) {
+		t.Error("bitmaps with the same values should be equal")
+	}
+	b.Del(5000)
+	b.Set(20000)
+	if a.Equal(b) {
+		t.Error("bitmaps with different values should not be equal")
+	}
+	b.Set(5000)
+	b.Del(20000)
+	b.Set(1 << 20)
+	if a.Equal(b) {
+		t.Error("bitmaps with different containers should not be equal")
+	}
+}
+
+func TestRoaring_Bounds(t *testing.T) {
+	r := NewRoaring()
+	r.Set(math.MaxUint32)
+	if !r.Check(math.MaxUint32) || r.Check(math.MaxUint32+1) {
+		t.Error("Check() at the top of the range returned a wrong result")
+	}
+	r.Del(math.MaxUint32 + 1)
+	r.Del(12345)
+	if r.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", r.Count())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Set() beyond 32 bits should panic")
+		}
+	}()
+	r.Set(math.MaxUint32 + 1)
+}
+
+func TestRoaring_All(t *testing.T) {
+	r := NewRoaring()
+	want := []uint{1, 70000, 1 << 31}
+	for _, v := range want {
+		r.Set(v)
+	}
+	if got := slices.Collect(r.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v", got)
+	}
+	n := 0
+	for range r.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All() did not stop, n = %d", n)
+	}
+}
+
+// TestBitSet checks that every implementation can be used behind BitSet
+func TestBitSet(t *testing.T) {
+	impls := map[string]BitSet{
+		"dense":   NewBitMap(1),
+		"roaring": NewRoaring(),
+		"atomic":  NewAtomicBitmap(100001),
+	}
+	for name, b := range impls {
+		t.Run(name, func(t *testing.T) {
+			b.Set(3)
+			b.Set(100000)
+			b.Del(3)
+			if b.Check(3) || !b.Check(100000) || b.Count() != 1 {
+				t.Errorf("unexpected content %v", slices.Collect(b.All()))
+			}
+			// a value no implementation holds reads as clear
+			if b.Check(1 << 30) {
+				t.Error("Check() = true for a value never set")
+			}
+		})
+	}
+}
+
+func bitmapFrom(r *Roaring) *Bitmap {
+	b := NewBitMap(1)
+	for v := range r.All() {
+		b.Set(v)
+	}
+	return b
+}
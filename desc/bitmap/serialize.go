@@ -0,0 +1,414 @@
+package bitmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrInvalidFormat      = errors.New("invalid bitmap encoding")
+	ErrUnsupportedVersion = errors.New("unsupported bitmap encoding version")
+	ErrChecksum           = errors.New("bitmap checksum mismatch")
+)
+
+// The dense Bitmap is encoded as
+//
+//	magic    [4]byte "GUBM"
+//	version  uint8
+//	words    uint64, the number of 64-bit words
+//	data     words * uint64
+//	checksum uint32, CRC-32 (IEEE) of everything before it
+//
+// with every integer in little endian.
+const (
+	bitmapVersion    = 1
+	bitmapHeaderSize = 4 + 1 + 8
+	// readChunkWords bounds the memory allocated ahead of the data actually
+	// read, so that a corrupt word count cannot trigger a huge allocation
+	readChunkWords = 1 << 12
+)
+
+var bitmapMagic = [4]byte{'G', 'U', 'B', 'M'}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(bitmapHeaderSize + 8*len(b.words) + 4)
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it replaces the
+// content of b
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	rd := bytes.NewReader(data)
+	if _, err := b.ReadFrom(rd); err != nil {
+		return err
+	}
+	if rd.Len() != 0 {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// WriteTo implements io.WriterTo
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	crc := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+	cw := &countingWriter{w: bw}
+
+	var header [bitmapHeaderSize]byte
+	copy(header[:], bitmapMagic[:])
+	header[4] = bitmapVersion
+	binary.LittleEndian.PutUint64(header[5:], uint64(len(b.words)))
+	cw.Write(header[:])
+	var word [8]byte
+	for _, v := range b.words {
+		binary.LittleEndian.PutUint64(word[:], v)
+		cw.Write(word[:])
+	}
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	n, err := w.Write(binary.LittleEndian.AppendUint32(nil, crc.Sum32()))
+	return cw.n + int64(n), err
+}
+
+// ReadFrom implements io.ReaderFrom, it replaces the content of b
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	crc := crc32.NewIEEE()
+	cr := &countingReader{r: io.TeeReader(r, crc)}
+
+	var header [bitmapHeaderSize]byte
+	if err := cr.readFull(header[:]); err != nil {
+		return cr.n, err
+	}
+	if [4]byte(header[:4]) != bitmapMagic {
+		return cr.n, ErrInvalidFormat
+	}
+	if header[4] != bitmapVersion {
+		return cr.n, ErrUnsupportedVersion
+	}
+	count := binary.LittleEndian.Uint64(header[5:])
+
+	var words []uint64
+	chunk := make([]byte, 8*readChunkWords)
+	for remaining := count; remaining > 0; {
+		n := min(remaining, readChunkWords)
+		buf := chunk[:8*n]
+		if err := cr.readFull(buf); err != nil {
+			return cr.n, err
+		}
+		for i := 0; i < len(buf); i += 8 {
+			words = append(words, binary.LittleEndian.Uint64(buf[i:]))
+		}
+		remaining -= n
+	}
+
+	sum := crc.Sum32()
+	var trailer [4]byte
+	if err := cr.readFull(trailer[:]); err != nil {
+		return cr.n, err
+	}
+	if binary.LittleEndian.Uint32(trailer[:]) != sum {
+		return cr.n, ErrChecksum
+	}
+	b.words = words
+	return cr.n, nil
+}
+
+// The Roaring bitmap follows the portable Roaring serialization format
+// (https://github.com/RoaringBitmap/RoaringFormatSpec) shared by the C, Java
+// and Go implementations, so the bytes can be exchanged with them.
+const (
+	serialCookieNoRuns = 12346
+	serialCookie       = 12347
+	noOffsetThreshold  = 4
+	maxContainers      = 1 << 16
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (r *Roaring) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it replaces the
+// content of r
+func (r *Roaring) UnmarshalBinary(data []byte) error {
+	rd := bytes.NewReader(data)
+	if _, err := r.ReadFrom(rd); err != nil {
+		return err
+	}
+	if rd.Len() != 0 {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// WriteTo implements io.WriterTo
+func (r *Roaring) WriteTo(w io.Writer) (int64, error) {
+	size := len(r.containers)
+	hasRuns := false
+	for _, c := range r.containers {
+		if _, ok := c.(runContainer); ok {
+			hasRuns = true
+			break
+		}
+	}
+
+	var header []byte
+	if hasRuns {
+		header = binary.LittleEndian.AppendUint16(header, serialCookie)
+		header = binary.LittleEndian.AppendUint16(header, uint16(size-1))
+		runFlags := make([]byte, (size+7)/8)
+		for i, c := range r.containers {
+			if _, ok := c.(runContainer); ok {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		header = append(header, runFlags...)
+	} else {
+		header = binary.LittleEndian.AppendUint32(header, serialCookieNoRuns)
+		header = binary.LittleEndian.AppendUint32(header, uint32(size))
+	}
+	for i, c := range r.containers {
+		header = binary.LittleEndian.AppendUint16(header, r.keys[i])
+		header = binary.LittleEndian.AppendUint16(header, uint16(c.cardinality()-1))
+	}
+	if !hasRuns || size >= noOffsetThreshold {
+		offset := len(header) + 4*size
+		for _, c := range r.containers {
+			header = binary.LittleEndian.AppendUint32(header, uint32(offset))
+			offset += containerSize(c)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	cw := &countingWriter{w: bw}
+	cw.Write(header)
+	for _, c := range r.containers {
+		cw.Write(appendContainer(nil, c))
+	}
+	if cw.err == nil {
+		cw.err = bw.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func containerSize(c container) int {
+	switch c := c.(type) {
+	case arrayContainer:
+		return 2 * len(c)
+	case runContainer:
+		return 2 + 4*len(c)
+	default:
+		return bitmapBytes
+	}
+}
+
+func appendContainer(buf []byte, c container) []byte {
+	switch c := c.(type) {
+	case arrayContainer:
+		for _, x := range c {
+			buf = binary.LittleEndian.AppendUint16(buf, x)
+		}
+	case runContainer:
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(c)))
+		for _, e := range c {
+			buf = binary.LittleEndian.AppendUint16(buf, e.start)
+			buf = binary.LittleEndian.AppendUint16(buf, e.last-e.start)
+		}
+	case *bitmapContainer:
+		for _, w := range c.words {
+			buf = binary.LittleEndian.AppendUint64(buf, w)
+		}
+	}
+	return buf
+}
+
+// ReadFrom implements io.ReaderFrom, it replaces the content of r. The input
+// is fully validated: keys must be increasing, containers well formed and
+// their cardinalities and offsets consistent with the header.
+func (r *Roaring) ReadFrom(rd io.Reader) (int64, error) {
+	cr := &countingReader{r: rd}
+
+	var cookie [4]byte
+	if err := cr.readFull(cookie[:]); err != nil {
+		return cr.n, err
+	}
+	var (
+		size     int
+		runFlags []byte
+	)
+	switch {
+	case binary.LittleEndian.Uint32(cookie[:]) == serialCookieNoRuns:
+		var b [4]byte
+		if err := cr.readFull(b[:]); err != nil {
+			return cr.n, err
+		}
+		n := binary.LittleEndian.Uint32(b[:])
+		if n > maxContainers {
+			return cr.n, ErrInvalidFormat
+		}
+		size = int(n)
+	case binary.LittleEndian.Uint16(cookie[:2]) == serialCookie:
+		size = int(binary.LittleEndian.Uint16(cookie[2:])) + 1
+		runFlags = make([]byte, (size+7)/8)
+		if err := cr.readFull(runFlags); err != nil {
+			return cr.n, err
+		}
+	default:
+		return cr.n, ErrInvalidFormat
+	}
+	isRun := func(i int) bool {
+		return runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+	}
+
+	descriptive := make([]byte, 4*size)
+	if err := cr.readFull(descriptive); err != nil {
+		return cr.n, err
+	}
+	var offsets []byte
+	if runFlags == nil || size >= noOffsetThreshold {
+		offsets = make([]byte, 4*size)
+		if err := cr.readFull(offsets); err != nil {
+			return cr.n, err
+		}
+	}
+
+	keys := make([]uint16, size)
+	containers := make([]container, size)
+	for i := range containers {
+		keys[i] = binary.LittleEndian.Uint16(descriptive[4*i:])
+		card := int(binary.LittleEndian.Uint16(descriptive[4*i+2:])) + 1
+		if i > 0 && keys[i] <= keys[i-1] {
+			return cr.n, ErrInvalidFormat
+		}
+		if offsets != nil && int64(binary.LittleEndian.Uint32(offsets[4*i:])) != cr.n {
+			return cr.n, ErrInvalidFormat
+		}
+		var (
+			c   container
+			err error
+		)
+		switch {
+		case isRun(i):
+			c, err = readRunContainer(cr, card)
+		case card <= arrayMaxSize:
+			c, err = readArrayContainer(cr, card)
+		default:
+			c, err = readBitmapContainer(cr, card)
+		}
+		if err != nil {
+			return cr.n, err
+		}
+		containers[i] = c
+	}
+	r.keys, r.containers = keys, containers
+	return cr.n, nil
+}
+
+func readArrayContainer(cr *countingReader, card int) (container, error) {
+	buf := make([]byte, 2*card)
+	if err := cr.readFull(buf); err != nil {
+		return nil, err
+	}
+	a := make(arrayContainer, card)
+	for i := range a {
+		a[i] = binary.LittleEndian.Uint16(buf[2*i:])
+		if i > 0 && a[i] <= a[i-1] {
+			return nil, ErrInvalidFormat
+		}
+	}
+	return a, nil
+}
+
+func readBitmapContainer(cr *countingReader, card int) (container, error) {
+	buf := make([]byte, bitmapBytes)
+	if err := cr.readFull(buf); err != nil {
+		return nil, err
+	}
+	b := &bitmapContainer{}
+	for i := range b.words {
+		b.words[i] = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+	c := b.normalize()
+	if c.cardinality() != card {
+		return nil, ErrInvalidFormat
+	}
+	return c, nil
+}
+
+func readRunContainer(cr *countingReader, card int) (container, error) {
+	var b [2]byte
+	if err := cr.readFull(b[:]); err != nil {
+		return nil, err
+	}
+	n := int(binary.LittleEndian.Uint16(b[:]))
+	buf := make([]byte, 4*n)
+	if err := cr.readFull(buf); err != nil {
+		return nil, err
+	}
+	rc := make(runContainer, n)
+	total := 0
+	for i := range rc {
+		start := int(binary.LittleEndian.Uint16(buf[4*i:]))
+		last := start + int(binary.LittleEndian.Uint16(buf[4*i+2:]))
+		// runs must fit in the container and be sorted without overlapping
+		if last > 0xffff || (i > 0 && start <= int(rc[i-1].last)) {
+			return nil, ErrInvalidFormat
+		}
+		rc[i] = run{start: uint16(start), last: uint16(last)}
+		total += last - start + 1
+	}
+	if total != card {
+		return nil, ErrInvalidFormat
+	}
+	return rc, nil
+}
+
+// countingWriter remembers the first error so that a sequence of writes can
+// be checked once at the end
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) {
+	if c.err != nil {
+		return
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// readFull reports a truncated input as ErrInvalidFormat
+func (c *countingReader) readFull(p []byte) error {
+	n, err := io.ReadFull(c.r, p)
+	c.n += int64(n)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrInvalidFormat
+	}
+	return err
+}
@@ -0,0 +1,291 @@
+package bitmap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBitmap_MarshalBinary(t *testing.T) {
+	b := NewBitMap(200)
+	for _, v := range []uint{0, 63, 64, 200} {
+		b.Set(v)
+	}
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != bitmapHeaderSize+8*len(b.words)+4 {
+		t.Errorf("encoded %d bytes", len(data))
+	}
+	if !bytes.Equal(data[:5], []byte("GUBM\x01")) {
+		t.Errorf("unexpected header %q", data[:5])
+	}
+
+	got := NewBitMap(1)
+	got.Set(5000)
+	if err = got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(b) || got.Len() != b.Len() {
+		t.Errorf("round trip = %v", slices.Collect(got.All()))
+	}
+
+	tests := []struct {
+		name string
+		data func() []byte
+		err  error
+	}{
+		{name: "empty", data: func() []byte { return nil }, err: ErrInvalidFormat},
+		{name: "truncated", data: func() []byte { return data[:len(data)-1] }, err: ErrInvalidFormat},
+		{name: "trailing data", data: func() []byte { return append(slices.Clone(data), 0) }, err: ErrInvalidFormat},
+		{name: "bad magic", data: func() []byte { d := slices.Clone(data); d[0] = 'X'; return d }, err: ErrInvalidFormat},
+		{name: "future version", data: func() []byte { d := slices.Clone(data); d[4] = 2; return d }, err: ErrUnsupportedVersion},
+		{name: "flipped bit", data: func() []byte { d := slices.Clone(data); d[20] ^= 1; return d }, err: ErrChecksum},
+		{name: "huge word count", data: func() []byte { d := slices.Clone(data); d[12] = 0xff; return d }, err: ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewBitMap(1).UnmarshalBinary(tt.data()); !errors.Is(err, tt.err) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBitmap_WriteTo(t *testing.T) {
+	b := NewBitMap(1)
+	b.SetRange(100, 100000)
+	var buf bytes.Buffer
+	n, err := b.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("WriteTo() = %d, %v, buffer holds %d bytes", n, err, buf.Len())
+	}
+	// a second bitmap in the same stream must be left unread
+	NewBitMap(1).WriteTo(&buf)
+
+	got := NewBitMap(1)
+	m, err := got.ReadFrom(&buf)
+	if err != nil || m != n {
+		t.Fatalf("ReadFrom() = %d, %v, want %d", m, err, n)
+	}
+	if !got.Equal(b) {
+		t.Error("stream round trip lost bits")
+	}
+	if _, err = NewBitMap(1).ReadFrom(&buf); err != nil {
+		t.Errorf("second bitmap: %v", err)
+	}
+
+	if _, err = b.WriteTo(failingWriter{}); err == nil {
+		t.Error("WriteTo() should report write errors")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// portable format samples built by hand from the Roaring format spec
+var roaringSamples = []struct {
+	name   string
+	values func() *Roaring
+	hex    string
+}{
+	{
+		name: "empty",
+		values: func() *Roaring {
+			return NewRoaring()
+		},
+		hex: "3a300000" + "00000000",
+	},
+	{
+		name: "arrays",
+		values: func() *Roaring {
+			r := NewRoaring()
+			for _, v := range []uint{1, 2, 3, 1<<16 + 5} {
+				r.Set(v)
+			}
+			return r
+		},
+		// cookie, container count, (key, cardinality-1) pairs, offsets, values
+		hex: "3a300000" + "02000000" + "00000200" + "01000000" + "18000000" + "1e000000" +
+			"010002000300" + "0500",
+	},
+	{
+		name: "run",
+		values: func() *Roaring {
+			r := NewRoaring()
+			for v := uint(0); v < 100; v++ {
+				r.Set(v)
+			}
+			r.RunOptimize()
+			return r
+		},
+		// cookie with count-1, run flags, (key, cardinality-1), no offsets
+		// below four containers, run count, (start, length-1)
+		hex: "3b300000" + "01" + "00006300" + "0100" + "00006300",
+	},
+}
+
+func TestRoaring_PortableFormat(t *testing.T) {
+	for _, tt := range roaringSamples {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.values()
+			data, err := r.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(data); got != tt.hex {
+				t.Errorf("MarshalBinary() = %s, want %s", got, tt.hex)
+			}
+			want, _ := hex.DecodeString(tt.hex)
+			got := NewRoaring()
+			got.Set(999999)
+			if err = got.UnmarshalBinary(want); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(r) {
+				t.Errorf("UnmarshalBinary() = %v", slices.Collect(got.All()))
+			}
+		})
+	}
+}
+
+func TestRoaring_RoundTrip(t *testing.T) {
+	r := NewRoaring()
+	// an array, a bitmap and, after RunOptimize, runs in several containers
+	for v := uint(0); v < 10; v++ {
+		r.Set(v * 3)
+	}
+	for v := uint(1 << 16); v < 1<<16+20000; v += 2 {
+		r.Set(v)
+	}
+	for k := uint(2); k < 8; k++ {
+		for v := uint(0); v < 3000; v++ {
+			r.Set(k<<16 | v)
+		}
+	}
+	for _, optimize := range []bool{false, true} {
+		if optimize {
+			r.RunOptimize()
+		}
+		var buf bytes.Buffer
+		n, err := r.WriteTo(&buf)
+		if err != nil || n != int64(buf.Len()) {
+			t.Fatalf("WriteTo() = %d, %v", n, err)
+		}
+		got := NewRoaring()
+		if m, err := got.ReadFrom(&buf); err != nil || m != n {
+			t.Fatalf("ReadFrom() = %d, %v, want %d", m, err, n)
+		}
+		if !got.Equal(r) || got.Count() != r.Count() {
+			t.Errorf("optimize=%v: round trip lost values", optimize)
+		}
+	}
+	if _, err := r.WriteTo(failingWriter{}); err == nil {
+		t.Error("WriteTo() should report write errors")
+	}
+}
+
+func TestRoaring_UnmarshalBinaryErrors(t *testing.T) {
+	arrays, _ := hex.DecodeString(roaringSamples[1].hex)
+	runs, _ := hex.DecodeString(roaringSamples[2].hex)
+	patch := func(data []byte, i int, b ...byte) []byte {
+		d := slices.Clone(data)
+		copy(d[i:], b)
+		return d
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "bad cookie", data: patch(arrays, 0, 0)},
+		{name: "too many containers", data: patch(arrays, 4, 0xff, 0xff, 0xff)},
+		{name: "truncated header", data: arrays[:10]},
+		{name: "truncated data", data: arrays[:len(arrays)-1]},
+		{name: "trailing data", data: append(slices.Clone(arrays), 0)},
+		{name: "decreasing keys", data: patch(arrays, 12, 0, 0)},
+		{name: "wrong offset", data: patch(arrays, 16, 0x19)},
+		{name: "unsorted array", data: patch(arrays, 26, 0)},
+		{name: "truncated run flags", data: runs[:4]},
+		{name: "run cardinality", data: patch(runs, 7, 0x64)},
+		{name: "run overflow", data: patch(runs, 11, 0xff, 0xff, 0xff, 0xff)},
+		{name: "truncated runs", data: runs[:len(runs)-2]},
+		{name: "truncated run count", data: runs[:10]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewRoaring().UnmarshalBinary(tt.data); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+
+	// a bitmap container whose bits disagree with the declared cardinality
+	r := NewRoaring()
+	for v := uint(0); v < 5000; v++ {
+		r.Set(v)
+	}
+	data, _ := r.MarshalBinary()
+	data[len(data)-1] = 0x80
+	if err := NewRoaring().UnmarshalBinary(data); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("bitmap cardinality: error = %v", err)
+	}
+	if err := NewRoaring().UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("truncated bitmap: error = %v", err)
+	}
+}
+
+func FuzzBitmap_UnmarshalBinary(f *testing.F) {
+	b := NewBitMap(300)
+	b.Set(7)
+	b.Set(300)
+	data, _ := b.MarshalBinary()
+	f.Add(data)
+	f.Add([]byte("GUBM\x01"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got := NewBitMap(1)
+		if err := got.UnmarshalBinary(data); err != nil {
+			return
+		}
+		// whatever decodes must encode back to the same bytes
+		again, err := got.MarshalBinary()
+		if err != nil || !bytes.Equal(again, data) {
+			t.Errorf("re-encoding %x gave %x, %v", data, again, err)
+		}
+	})
+}
+
+func FuzzRoaring_UnmarshalBinary(f *testing.F) {
+	for _, s := range roaringSamples {
+		data, _ := hex.DecodeString(s.hex)
+		f.Add(data)
+	}
+	r := NewRoaring()
+	for v := uint(0); v < 5000; v++ {
+		r.Set(v * 2)
+	}
+	data, _ := r.MarshalBinary()
+	f.Add(data)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		got := NewRoaring()
+		if err := got.UnmarshalBinary(data); err != nil {
+			return
+		}
+		// the decoded bitmap must be usable and survive a round trip
+		n := got.Count()
+		again, err := got.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		back := NewRoaring()
+		if err = back.UnmarshalBinary(again); err != nil || !back.Equal(got) || back.Count() != n {
+			t.Errorf("round trip of %x failed: %v", data, err)
+		}
+	})
+}
@@ -0,0 +1,250 @@
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"hash/fnv"
+	"math"
+
+	"github.com/victorwong171/go-utils/desc/bitmap"
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrIncompatible  = errors.New("bloom filters have different parameters")
+	ErrInvalidFormat = errors.New("invalid bloom filter encoding")
+)
+
+// Hasher hashes an item to 64 bits. The k positions of an item are derived
+// from this single hash by double hashing, so it only needs to be well
+// distributed, not cryptographic.
+type Hasher func(data []byte) uint64
+
+// FNV is the default Hasher, the 64-bit FNV-1a hash
+func FNV(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// Estimate returns the number of bits m and of hash functions k a filter
+// needs to hold n items with a false-positive rate of at most p. It panics
+// if p is not in (0, 1).
+func Estimate(n uint, p float64) (m, k uint) {
+	if p <= 0 || p >= 1 {
+		panic("bloom: false-positive rate must be in (0, 1)")
+	}
+	nf := float64(max(n, 1))
+	mf := math.Ceil(-nf * math.Log(p) / (math.Ln2 * math.Ln2))
+	kf := math.Round(mf / nf * math.Ln2)
+	return uint(mf), max(uint(kf), 1)
+}
+
+// locations calls fn with the k bit positions of data in a filter of m bits
+func locations(hash Hasher, data []byte, m uint64, k uint, fn func(uint64) bool) bool {
+	h1 := hash(data)
+	// the second hash is derived by remixing the first one, see Kirsch and
+	// Mitzenmacher, "Less hashing, same performance"
+	h2 := h1 ^ h1>>33
+	h2 *= 0xff51afd7ed558ccd
+	h2 ^= h2 >> 33
+	h2 |= 1
+	for i := uint64(0); i < uint64(k); i++ {
+		if !fn((h1 + i*h2) % m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter is a Bloom filter: a compact probabilistic set that never reports
+// a false negative and reports a false positive with a tunable probability.
+// Items cannot be removed, see CountingFilter for that. A Filter is not safe
+// for concurrent use.
+type Filter struct {
+	bits *bitmap.Bitmap
+	m    uint64
+	k    uint
+	hash Hasher
+}
+
+// MaxK is the largest number of hash positions per item. Estimate only
+// exceeds it for false-positive rates far below 1e-30.
+const MaxK = 128
+
+// NewFilter creates a filter of m bits using k hash positions per item, k
+// being capped at MaxK. A nil hash selects FNV.
+func NewFilter(m, k uint, hash Hasher) *Filter {
+	m, k = max(m, 1), min(max(k, 1), MaxK)
+	if hash == nil {
+		hash = FNV
+	}
+	// NewBitMap(max) holds the bits 0..max in max/64+1 words; max is kept
+	// positive because 0 selects a default size
+	return &Filter{bits: bitmap.NewBitMap(max(m-1, 1)), m: uint64(m), k: k, hash: hash}
+}
+
+// NewFilterWithEstimates creates a filter sized by Estimate for n items and
+// a false-positive rate of p
+func NewFilterWithEstimates(n uint, p float64, hash Hasher) *Filter {
+	m, k := Estimate(n, p)
+	return NewFilter(m, k, hash)
+}
+
+// M returns the number of bits of the filter
+func (f *Filter) M() uint {
+	return uint(f.m)
+}
+
+// K returns the number of hash positions per item
+func (f *Filter) K() uint {
+	return f.k
+}
+
+// Add adds data to the filter
+func (f *Filter) Add(data []byte) {
+	locations(f.hash, data, f.m, f.k, func(i uint64) bool {
+		f.bits.Set(uint(i))
+		return true
+	})
+}
+
+// AddString adds s to the filter
+func (f *Filter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test reports whether data may have been added. False means it certainly
+// was not.
+func (f *Filter) Test(data []byte) bool {
+	return locations(f.hash, data, f.m, f.k, func(i uint64) bool {
+		return f.bits.Test(uint(i))
+	})
+}
+
+// TestString reports whether s may have been added
+func (f *Filter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// EstimatedCount estimates the number of distinct items added from the
+// number of set bits
+func (f *Filter) EstimatedCount() uint {
+	x := float64(f.bits.Count())
+	m := float64(f.m)
+	if x >= m {
+		return math.MaxUint
+	}
+	return uint(math.Round(-m / float64(f.k) * math.Log(1-x/m)))
+}
+
+// Union adds the items of other, which must have been created with the same
+// m, k and Hasher; only m and k can be checked
+func (f *Filter) Union(other *Filter) error {
+	if f.m != other.m || f.k != other.k {
+		return ErrIncompatible
+	}
+	f.bits.Or(other.bits)
+	return nil
+}
+
+// Clear removes every item
+func (f *Filter) Clear() {
+	f.bits.ClearRange(0, uint(f.m))
+}
+
+// Clone returns a copy of f
+func (f *Filter) Clone() *Filter {
+	c := *f
+	c.bits = f.bits.Clone()
+	return &c
+}
+
+// A Filter is encoded as
+//
+//	magic   [4]byte "GUBF"
+//	version uint8
+//	m       uint64
+//	k       uint32
+//	bits    the encoding of a bitmap.Bitmap
+//	crc     uint32, the CRC-32 (IEEE) of everything before it
+//
+// with every integer in little endian. The Hasher is not encoded, the
+// decoding side must use the same one.
+const (
+	filterVersion    = 1
+	filterHeaderSize = 4 + 1 + 8 + 4
+)
+
+var filterMagic = [4]byte{'G', 'U', 'B', 'F'}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	bits, err := f.bits.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, filterHeaderSize+len(bits)+4)
+	buf = appendHeader(buf, filterMagic, f.m, f.k)
+	buf = append(buf, bits...)
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it replaces the
+// content and parameters of f but keeps its Hasher, or FNV if it has none
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	body, err := checkCRC(data)
+	if err != nil {
+		return err
+	}
+	m, k, rest, err := readHeader(body, filterMagic)
+	if err != nil {
+		return err
+	}
+	bits := bitmap.NewBitMap(1)
+	if err = bits.UnmarshalBinary(rest); err != nil {
+		return ErrInvalidFormat
+	}
+	// NewFilter allocates the fewest whole words holding m bits
+	if n := uint64(bits.Len()); m > n || n-m >= 64 {
+		return ErrInvalidFormat
+	}
+	if f.hash == nil {
+		f.hash = FNV
+	}
+	f.bits, f.m, f.k = bits, m, k
+	return nil
+}
+
+func appendHeader(buf []byte, magic [4]byte, m uint64, k uint) []byte {
+	buf = append(buf, magic[:]...)
+	buf = append(buf, filterVersion)
+	buf = binary.LittleEndian.AppendUint64(buf, m)
+	return binary.LittleEndian.AppendUint32(buf, uint32(k))
+}
+
+func readHeader(data []byte, magic [4]byte) (uint64, uint, []byte, error) {
+	if len(data) < filterHeaderSize || !bytes.Equal(data[:4], magic[:]) ||
+		data[4] != filterVersion {
+		return 0, 0, nil, ErrInvalidFormat
+	}
+	m := binary.LittleEndian.Uint64(data[5:])
+	k := binary.LittleEndian.Uint32(data[13:])
+	if m == 0 || k == 0 || k > MaxK {
+		return 0, 0, nil, ErrInvalidFormat
+	}
+	return m, uint(k), data[filterHeaderSize:], nil
+}
+
+// checkCRC verifies the CRC-32 ending data and returns what it covers
+func checkCRC(data []byte) ([]byte, error) {
+	if len(data) < 4 {
+		return nil, ErrInvalidFormat
+	}
+	body := data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(body):]) {
+		return nil, ErrInvalidFormat
+	}
+	return body, nil
+}
@@ -0,0 +1,273 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/maphash"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/victorwong171/go-utils/desc/bitmap"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		n    uint
+		p    float64
+		m, k uint
+	}{
+		{n: 1000, p: 0.01, m: 9586, k: 7},
+		{n: 1000000, p: 0.001, m: 14377588, k: 10},
+		{n: 0, p: 0.5, m: 2, k: 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%g", tt.n, tt.p), func(t *testing.T) {
+			if m, k := Estimate(tt.n, tt.p); m != tt.m || k != tt.k {
+				t.Errorf("Estimate() = %d, %d, want %d, %d", m, k, tt.m, tt.k)
+			}
+		})
+	}
+	for _, p := range []float64{0, 1, -0.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Estimate(%g) should panic", p)
+				}
+			}()
+			Estimate(10, p)
+		}()
+	}
+}
+
+// falsePositiveRate adds n items to a filter and returns the share of
+// trials other items that it reports as present
+func falsePositiveRate(t *testing.T, add func(string), test func(string) bool, n, trials int) float64 {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		add(fmt.Sprintf("member-%d", i))
+	}
+	for i := 0; i < n; i++ {
+		if !test(fmt.Sprintf("member-%d", i)) {
+			t.Fatalf("false negative for member-%d", i)
+		}
+	}
+	fp := 0
+	for i := 0; i < trials; i++ {
+		if test(fmt.Sprintf("other-%d", i)) {
+			fp++
+		}
+	}
+	return float64(fp) / float64(trials)
+}
+
+func TestFilter_FalsePositiveRate(t *testing.T) {
+	seed := maphash.MakeSeed()
+	hashers := map[string]Hasher{
+		"fnv":     nil,
+		"maphash": func(data []byte) uint64 { return maphash.Bytes(seed, data) },
+	}
+	for name, h := range hashers {
+		for _, p := range []float64{0.1, 0.01, 0.001} {
+			t.Run(fmt.Sprintf("%s/%g", name, p), func(t *testing.T) {
+				const n = 10000
+				f := NewFilterWithEstimates(n, p, h)
+				rate := falsePositiveRate(t, f.AddString, f.TestString, n, 200000)
+				// leave room for sampling noise
+				if rate > 1.5*p {
+					t.Errorf("false-positive rate %g, want at most %g", rate, p)
+				}
+				if c := f.EstimatedCount(); c < n*95/100 || c > n*105/100 {
+					t.Errorf("EstimatedCount() = %d, want about %d", c, n)
+				}
+			})
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f := NewFilter(0, 0, nil)
+	if f.M() != 1 || f.K() != 1 || f.TestString("a") {
+		t.Fatalf("M() = %d, K() = %d", f.M(), f.K())
+	}
+	f.AddString("a")
+	if !f.TestString("b") || f.EstimatedCount() == 0 {
+		t.Error("a full one bit filter holds everything")
+	}
+
+	f = NewFilter(1000, 3, nil)
+	f.AddString("a")
+	g := f.Clone()
+	g.AddString("b")
+	if f.TestString("b") || !g.TestString("a") || !g.TestString("b") {
+		t.Error("Clone() shares bits")
+	}
+	f.Clear()
+	if f.TestString("a") || f.EstimatedCount() != 0 {
+		t.Error("Clear() left items")
+	}
+}
+
+func TestFilter_Union(t *testing.T) {
+	a, b := NewFilter(1000, 4, nil), NewFilter(1000, 4, nil)
+	a.AddString("a")
+	b.AddString("b")
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	if !a.TestString("a") || !a.TestString("b") {
+		t.Error("Union() lost items")
+	}
+	for _, other := range []*Filter{NewFilter(1001, 4, nil), NewFilter(1000, 5, nil)} {
+		if err := a.Union(other); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("Union() error = %v, want %v", err, ErrIncompatible)
+		}
+	}
+}
+
+func TestFilter_MarshalBinary(t *testing.T) {
+	f := NewFilterWithEstimates(100, 0.01, nil)
+	for i := 0; i < 100; i++ {
+		f.AddString(fmt.Sprint(i))
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Filter
+	if err = got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.M() != f.M() || got.K() != f.K() {
+		t.Fatalf("parameters = %d, %d, want %d, %d", got.M(), got.K(), f.M(), f.K())
+	}
+	for i := 0; i < 100; i++ {
+		if !got.TestString(fmt.Sprint(i)) {
+			t.Fatalf("decoded filter lost %d", i)
+		}
+	}
+
+	corrupt := func(i int, b byte) []byte {
+		d := append([]byte(nil), data...)
+		d[i] = b
+		return d
+	}
+	tests := map[string][]byte{
+		"empty":         nil,
+		"bad magic":     corrupt(0, 'X'),
+		"bad version":   corrupt(4, 9),
+		"zero k":        append(append([]byte(nil), data[:13]...), append([]byte{0, 0, 0, 0}, data[17:]...)...),
+		"other m":       corrupt(6, 0xff),
+		"bad bitmap":    corrupt(len(data)-1, ^data[len(data)-1]),
+		"counting kind": corrupt(3, 'C'),
+	}
+	for name, d := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := new(Filter).UnmarshalBinary(d); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
+
+func TestFilter_MarshalBinarySizes(t *testing.T) {
+	filters := map[string]*Filter{
+		"one bit":       NewFilter(1, 1, nil),
+		"one word":      NewFilter(64, 2, nil),
+		"two words":     NewFilter(65, 2, nil),
+		"estimates n=1": NewFilterWithEstimates(1, 0.99, nil),
+	}
+	for name, f := range filters {
+		t.Run(name, func(t *testing.T) {
+			f.AddString("a")
+			data, err := f.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got Filter
+			if err = got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if got.M() != f.M() || !got.TestString("a") {
+				t.Errorf("decoded filter differs, M() = %d, want %d", got.M(), f.M())
+			}
+		})
+	}
+}
+
+func TestFilter_UnmarshalBinaryHugeM(t *testing.T) {
+	for _, bits := range []*bitmap.Bitmap{new(bitmap.Bitmap), bitmap.NewBitMap(63)} {
+		enc, err := bits.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range []uint64{65, math.MaxUint64, math.MaxUint64 - 63} {
+			data := seal(append(appendHeader(nil, filterMagic, m, 1), enc...))
+			if err := new(Filter).UnmarshalBinary(data); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("m = %d, %d bits: UnmarshalBinary() error = %v, want %v", m, bits.Len(), err, ErrInvalidFormat)
+			}
+		}
+	}
+}
+
+// seal appends the CRC-32 that ends every encoding
+func seal(data []byte) []byte {
+	return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+}
+
+func TestFilter_UnmarshalBinaryHeader(t *testing.T) {
+	f := NewFilter(1000, 4, nil)
+	f.AddString("a")
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := data[filterHeaderSize : len(data)-4]
+	// every header byte is covered by the checksum
+	for i := 0; i < filterHeaderSize; i++ {
+		d := slices.Clone(data)
+		d[i] ^= 0x01
+		if err := new(Filter).UnmarshalBinary(d); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("flipped header byte %d: UnmarshalBinary() error = %v", i, err)
+		}
+	}
+	// a well formed header with an absurd k is refused too
+	for _, k := range []uint{MaxK + 1, math.MaxUint32} {
+		d := seal(append(appendHeader(nil, filterMagic, 1000, k), bits...))
+		if err := new(Filter).UnmarshalBinary(d); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("k = %d: UnmarshalBinary() error = %v", k, err)
+		}
+	}
+	d := seal(append(appendHeader(nil, filterMagic, 1000, MaxK), bits...))
+	if err := new(Filter).UnmarshalBinary(d); err != nil {
+		t.Errorf("k = MaxK: UnmarshalBinary() error = %v", err)
+	}
+	if k := NewFilter(10, MaxK+1, nil).K(); k != MaxK {
+		t.Errorf("NewFilter() K() = %d, want MaxK", k)
+	}
+}
+
+func BenchmarkFilter_Add(b *testing.B) {
+	f := NewFilterWithEstimates(uint(b.N), 0.01, nil)
+	data := []byte("benchmark-item")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data[len(data)-1] = byte(i)
+		f.Add(data)
+	}
+}
+
+func BenchmarkFilter_Test(b *testing.B) {
+	f := NewFilterWithEstimates(1000000, 0.01, nil)
+	for i := 0; i < 1000000; i++ {
+		f.AddString(fmt.Sprint(i))
+	}
+	data := []byte("benchmark-item")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data[len(data)-1] = byte(i)
+		f.Test(data)
+	}
+}
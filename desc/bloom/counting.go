@@ -0,0 +1,159 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+)
+
+// CountingFilter is a Bloom filter that keeps an 8-bit counter instead of a
+// bit per position, so items can be removed again. A counter that reaches
+// 255 sticks there, as decrementing it could cause false negatives. A
+// CountingFilter is not safe for concurrent use.
+type CountingFilter struct {
+	counters []uint8
+	k        uint
+	hash     Hasher
+}
+
+// NewCountingFilter creates a filter of m counters using k hash positions
+// per item, k being capped at MaxK. A nil hash selects FNV.
+func NewCountingFilter(m, k uint, hash Hasher) *CountingFilter {
+	m, k = max(m, 1), min(max(k, 1), MaxK)
+	if hash == nil {
+		hash = FNV
+	}
+	return &CountingFilter{counters: make([]uint8, m), k: k, hash: hash}
+}
+
+// NewCountingFilterWithEstimates creates a filter sized by Estimate for n
+// items and a false-positive rate of p
+func NewCountingFilterWithEstimates(n uint, p float64, hash Hasher) *CountingFilter {
+	m, k := Estimate(n, p)
+	return NewCountingFilter(m, k, hash)
+}
+
+func (f *CountingFilter) locations(data []byte, fn func(uint64) bool) bool {
+	return locations(f.hash, data, uint64(len(f.counters)), f.k, fn)
+}
+
+// M returns the number of counters of the filter
+func (f *CountingFilter) M() uint {
+	return uint(len(f.counters))
+}
+
+// K returns the number of hash positions per item
+func (f *CountingFilter) K() uint {
+	return f.k
+}
+
+// Add adds data to the filter
+func (f *CountingFilter) Add(data []byte) {
+	f.locations(data, func(i uint64) bool {
+		if f.counters[i] < math.MaxUint8 {
+			f.counters[i]++
+		}
+		return true
+	})
+}
+
+// AddString adds s to the filter
+func (f *CountingFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Remove removes one occurrence of data and reports whether it may have
+// been present. Removing an item that was never added can remove others.
+func (f *CountingFilter) Remove(data []byte) bool {
+	if !f.Test(data) {
+		return false
+	}
+	f.locations(data, func(i uint64) bool {
+		// an item that was never added may hit a counter once more than
+		// it was incremented, so check every decrement
+		if c := f.counters[i]; c > 0 && c < math.MaxUint8 {
+			f.counters[i]--
+		}
+		return true
+	})
+	return true
+}
+
+// RemoveString removes one occurrence of s
+func (f *CountingFilter) RemoveString(s string) bool {
+	return f.Remove([]byte(s))
+}
+
+// Test reports whether data may be present. False means it certainly is not.
+func (f *CountingFilter) Test(data []byte) bool {
+	return f.locations(data, func(i uint64) bool {
+		return f.counters[i] > 0
+	})
+}
+
+// TestString reports whether s may be present
+func (f *CountingFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Union adds the counts of other, which must have been created with the
+// same m, k and Hasher; only m and k can be checked
+func (f *CountingFilter) Union(other *CountingFilter) error {
+	if len(f.counters) != len(other.counters) || f.k != other.k {
+		return ErrIncompatible
+	}
+	for i, c := range other.counters {
+		f.counters[i] = uint8(min(int(f.counters[i])+int(c), math.MaxUint8))
+	}
+	return nil
+}
+
+// Clear removes every item
+func (f *CountingFilter) Clear() {
+	clear(f.counters)
+}
+
+// Filter returns a plain Filter holding the items present in f
+func (f *CountingFilter) Filter() *Filter {
+	b := NewFilter(uint(len(f.counters)), f.k, f.hash)
+	for i, c := range f.counters {
+		if c > 0 {
+			b.bits.Set(uint(i))
+		}
+	}
+	return b
+}
+
+// A CountingFilter is encoded with the header of a Filter and the magic
+// "GUBC", followed by the m counters and a CRC-32 (IEEE) of everything
+// before it.
+var countingMagic = [4]byte{'G', 'U', 'B', 'C'}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, filterHeaderSize+len(f.counters)+4)
+	buf = appendHeader(buf, countingMagic, uint64(len(f.counters)), f.k)
+	buf = append(buf, f.counters...)
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it replaces the
+// content and parameters of f but keeps its Hasher, or FNV if it has none
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	body, err := checkCRC(data)
+	if err != nil {
+		return err
+	}
+	m, k, rest, err := readHeader(body, countingMagic)
+	if err != nil {
+		return err
+	}
+	if uint64(len(rest)) != m {
+		return ErrInvalidFormat
+	}
+	if f.hash == nil {
+		f.hash = FNV
+	}
+	f.counters, f.k = append([]uint8(nil), rest[:m]...), k
+	return nil
+}
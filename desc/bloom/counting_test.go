@@ -0,0 +1,164 @@
+package bloom
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCountingFilter_FalsePositiveRate(t *testing.T) {
+	const (
+		n = 10000
+		p = 0.01
+	)
+	f := NewCountingFilterWithEstimates(n, p, nil)
+	if rate := falsePositiveRate(t, f.AddString, f.TestString, n, 200000); rate > 1.5*p {
+		t.Errorf("false-positive rate %g, want at most %g", rate, p)
+	}
+	// after removing every member the filter must be empty again
+	for i := 0; i < n; i++ {
+		if !f.RemoveString(fmt.Sprintf("member-%d", i)) {
+			t.Fatalf("member-%d was not present", i)
+		}
+	}
+	for _, c := range f.counters {
+		if c != 0 {
+			t.Fatal("counters left after removing everything")
+		}
+	}
+}
+
+func TestCountingFilter_Remove(t *testing.T) {
+	f := NewCountingFilter(1000, 4, nil)
+	if f.M() != 1000 || f.K() != 4 {
+		t.Fatalf("M() = %d, K() = %d", f.M(), f.K())
+	}
+	f.AddString("a")
+	f.AddString("a")
+	f.AddString("b")
+	if f.RemoveString("c") {
+		t.Error("Remove() of an absent item reported true")
+	}
+	if !f.RemoveString("a") || !f.TestString("a") {
+		t.Error("the second occurrence of a should remain")
+	}
+	if !f.RemoveString("a") || f.TestString("a") || !f.TestString("b") {
+		t.Error("removing a twice should leave only b")
+	}
+
+	// saturated counters never go down, so the item stays present
+	s := NewCountingFilter(10, 1, nil)
+	for i := 0; i < 300; i++ {
+		s.AddString("x")
+	}
+	for i := 0; i < 300; i++ {
+		s.RemoveString("x")
+	}
+	if !s.TestString("x") {
+		t.Error("a saturated counter was decremented")
+	}
+	s.Clear()
+	if s.TestString("x") {
+		t.Error("Clear() left items")
+	}
+}
+
+func TestCountingFilter_RemoveRepeatedLocation(t *testing.T) {
+	// with an odd number of counters the second hash, which is odd, can be
+	// a multiple of it and repeat a position
+	f := NewCountingFilter(9, 3, nil)
+	positions := func(s string) []uint64 {
+		var res []uint64
+		f.locations([]byte(s), func(i uint64) bool {
+			res = append(res, i)
+			return true
+		})
+		return res
+	}
+	// find an added item a with distinct positions and an absent item b
+	// hitting one of them twice and nothing else
+	var a, b string
+	for i := 0; a == "" || b == ""; i++ {
+		p := positions(fmt.Sprint(i))
+		distinct := p[0] != p[1] && p[1] != p[2] && p[0] != p[2]
+		switch {
+		case a == "" && distinct:
+			a = fmt.Sprint(i)
+		case a != "" && !distinct && !slices.ContainsFunc(p, func(x uint64) bool {
+			return !slices.Contains(positions(a), x)
+		}):
+			b = fmt.Sprint(i)
+		}
+	}
+	f.AddString(a)
+	if !f.RemoveString(b) {
+		t.Fatalf("Remove(%q) should pass the membership test", b)
+	}
+	for i, c := range f.counters {
+		if c > 1 {
+			t.Errorf("counter %d = %d after a removal, want at most 1", i, c)
+		}
+	}
+}
+
+func TestCountingFilter_Union(t *testing.T) {
+	a, b := NewCountingFilter(1000, 4, nil), NewCountingFilter(1000, 4, nil)
+	a.AddString("x")
+	b.AddString("x")
+	b.AddString("y")
+	if err := a.Union(b); err != nil {
+		t.Fatal(err)
+	}
+	a.RemoveString("x")
+	if !a.TestString("x") || !a.TestString("y") {
+		t.Error("Union() should add the counts")
+	}
+	if err := a.Union(NewCountingFilter(1000, 3, nil)); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Union() error = %v, want %v", err, ErrIncompatible)
+	}
+
+	plain := a.Filter()
+	if plain.M() != a.M() || !plain.TestString("x") || !plain.TestString("y") || plain.TestString("z") {
+		t.Error("Filter() does not match the counting filter")
+	}
+}
+
+func TestCountingFilter_MarshalBinary(t *testing.T) {
+	f := NewCountingFilter(500, 3, nil)
+	f.AddString("a")
+	f.AddString("a")
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got CountingFilter
+	if err = got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.M() != 500 || got.K() != 3 || !got.RemoveString("a") || !got.TestString("a") {
+		t.Error("decoded filter lost counts")
+	}
+
+	corrupt := func(i int, b byte) []byte {
+		d := append([]byte(nil), data...)
+		d[i] = b
+		return d
+	}
+	tests := map[string][]byte{
+		"truncated":   data[:len(data)-1],
+		"checksum":    corrupt(20, 9),
+		"huge m":      corrupt(12, 0xff),
+		"plain kind":  corrupt(3, 'F'),
+		"header only": data[:filterHeaderSize],
+		"huge k":      seal(append(appendHeader(nil, countingMagic, 500, math.MaxUint32), data[filterHeaderSize:len(data)-4]...)),
+	}
+	for name, d := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := new(CountingFilter).UnmarshalBinary(d); !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
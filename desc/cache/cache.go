@@ -0,0 +1,365 @@
+package cache
+
+import (
+	"iter"
+	"time"
+
+	"github.com/victorwong171/go-utils/desc/list_node"
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrInvalidCfg = errors.New("invalid cache config")
+)
+
+// Cache is a bounded in-memory key value store. Once a bound is reached,
+// adding an entry evicts others in the order of the eviction policy.
+// Expired entries are removed lazily, when they are accessed or by Purge.
+type Cache[K comparable, V any] interface {
+	// Get returns the value of key and records the access for the policy
+	// and the stats
+	Get(key K) (V, bool)
+	// Peek returns the value of key without recording the access
+	Peek(key K) (V, bool)
+	// Contains reports whether key holds a live entry, without recording
+	// the access
+	Contains(key K) bool
+	// Set stores value under key, evicting other entries to make room. It
+	// returns false, dropping any previous value of key, when the entry
+	// alone costs more than the whole cache may hold.
+	Set(key K, value V) bool
+	// Delete removes key and reports whether it held a live entry
+	Delete(key K) bool
+	// Purge removes the expired entries and returns how many there were
+	Purge() int
+	// Clear removes every entry, the stats are kept
+	Clear()
+	// Len returns the number of entries, expired ones not removed yet
+	// included
+	Len() int
+	// Cost returns the total cost of the entries
+	Cost() int64
+	// Stats returns the counters since the cache was created
+	Stats() Stats
+	// All returns an iterator over the live entries, from the one the policy
+	// protects most to the next to be evicted. The cache must not be
+	// modified during the iteration.
+	All() iter.Seq2[K, V]
+}
+
+// EvictReason tells OnEvict why an entry left the cache
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry made room for another one
+	EvictCapacity EvictReason = iota
+	// EvictExpired means the entry outlived its TTL
+	EvictExpired
+	// EvictDeleted means the entry was removed by Delete or Clear
+	EvictDeleted
+	// EvictReplaced means Set stored a new value under the key
+	EvictReplaced
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictDeleted:
+		return "deleted"
+	case EvictReplaced:
+		return "replaced"
+	}
+	return "unknown"
+}
+
+// Cfg configures a cache. A zero bound means no bound.
+type Cfg[K comparable, V any] struct {
+	// MaxEntries bounds the number of entries
+	MaxEntries int
+	// MaxCost bounds the total cost of the entries, as given by Cost
+	MaxCost int64
+	// Cost returns the cost of an entry, typically its size in bytes. It must
+	// not be negative; by default every entry costs 1.
+	Cost func(key K, value V) int64
+	// TTL is how long an entry lives after it was last set
+	TTL time.Duration
+	// OnEvict is called synchronously whenever an entry leaves the cache or
+	// its value is replaced. In a sharded cache it runs with the shard locked
+	// and must not use the cache.
+	OnEvict func(key K, value V, reason EvictReason)
+	// Now is the clock used for the TTL, time.Now by default
+	Now func() time.Time
+}
+
+// Stats counts the outcome of Get calls and the entries removed by the
+// cache itself
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+// HitRatio returns the share of Get calls that found a live entry
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Hits:        s.Hits + other.Hits,
+		Misses:      s.Misses + other.Misses,
+		Evictions:   s.Evictions + other.Evictions,
+		Expirations: s.Expirations + other.Expirations,
+	}
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	cost      int64
+	expiresAt time.Time
+	// elem is the element of the entry in the list of its policy, bucket the
+	// frequency bucket holding it for LFU
+	elem   *list_node.Element[*entry[K, V]]
+	bucket *list_node.Element[*lfuBucket[K, V]]
+}
+
+// policy orders the entries of a cache for eviction
+type policy[K comparable, V any] interface {
+	// add tracks a new entry
+	add(e *entry[K, V])
+	// access records a Get hit
+	access(e *entry[K, V])
+	// update records a Set of an existing entry
+	update(e *entry[K, V])
+	remove(e *entry[K, V])
+	clear()
+	// victims yields the entries in eviction order
+	victims() iter.Seq[*entry[K, V]]
+	// all yields the entries in reverse eviction order
+	all() iter.Seq[*entry[K, V]]
+}
+
+type cache[K comparable, V any] struct {
+	cfg    Cfg[K, V]
+	items  map[K]*entry[K, V]
+	policy policy[K, V]
+	cost   int64
+	stats  Stats
+	// expiryOrdered is set when the victims come in expiry order, so Purge
+	// can stop at the first live entry
+	expiryOrdered bool
+}
+
+func newCache[K comparable, V any](cfg Cfg[K, V], p policy[K, V]) (*cache[K, V], error) {
+	if cfg.MaxEntries < 0 || cfg.MaxCost < 0 || cfg.TTL < 0 {
+		return nil, ErrInvalidCfg
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &cache[K, V]{
+		cfg:    cfg,
+		items:  make(map[K]*entry[K, V]),
+		policy: p,
+	}, nil
+}
+
+func (c *cache[K, V]) expired(e *entry[K, V], now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func (c *cache[K, V]) costOf(key K, value V) int64 {
+	if c.cfg.Cost == nil {
+		return 1
+	}
+	return c.cfg.Cost(key, value)
+}
+
+// live returns the entry of key unless it is missing or expired
+func (c *cache[K, V]) live(key K) (*entry[K, V], bool) {
+	e, ok := c.items[key]
+	if !ok || c.expired(e, c.cfg.Now()) {
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	e, ok := c.items[key]
+	if ok && c.expired(e, c.cfg.Now()) {
+		c.remove(e, EvictExpired)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.policy.access(e)
+	return e.value, true
+}
+
+func (c *cache[K, V]) Peek(key K) (V, bool) {
+	if e, ok := c.live(key); ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *cache[K, V]) Contains(key K) bool {
+	_, ok := c.live(key)
+	return ok
+}
+
+func (c *cache[K, V]) Set(key K, value V) bool {
+	cost := c.costOf(key, value)
+	e, exists := c.items[key]
+	if exists && c.expired(e, c.cfg.Now()) {
+		c.remove(e, EvictExpired)
+		exists = false
+	}
+	if c.cfg.MaxCost > 0 && cost > c.cfg.MaxCost {
+		if exists {
+			c.remove(e, EvictCapacity)
+		}
+		return false
+	}
+	var expiresAt time.Time
+	if c.cfg.TTL > 0 {
+		expiresAt = c.cfg.Now().Add(c.cfg.TTL)
+	}
+	if exists {
+		old := e.value
+		c.cost += cost - e.cost
+		e.value, e.cost, e.expiresAt = value, cost, expiresAt
+		c.policy.update(e)
+		c.notify(key, old, EvictReplaced)
+		c.evict(e, 0, 0)
+		return true
+	}
+	c.evict(nil, 1, cost)
+	e = &entry[K, V]{key: key, value: value, cost: cost, expiresAt: expiresAt}
+	c.items[key] = e
+	c.cost += cost
+	c.policy.add(e)
+	return true
+}
+
+// evict removes entries other than keep until extra more entries of
+// extraCost fit within the bounds
+func (c *cache[K, V]) evict(keep *entry[K, V], extra int, extraCost int64) {
+	now := c.cfg.Now()
+	for c.over(extra, extraCost) {
+		var victim *entry[K, V]
+		for v := range c.policy.victims() {
+			if v != keep {
+				victim = v
+				break
+			}
+		}
+		if victim == nil {
+			return
+		}
+		if c.expired(victim, now) {
+			c.remove(victim, EvictExpired)
+		} else {
+			c.remove(victim, EvictCapacity)
+		}
+	}
+}
+
+func (c *cache[K, V]) over(extra int, extraCost int64) bool {
+	return (c.cfg.MaxEntries > 0 && len(c.items)+extra > c.cfg.MaxEntries) ||
+		(c.cfg.MaxCost > 0 && c.cost+extraCost > c.cfg.MaxCost)
+}
+
+func (c *cache[K, V]) remove(e *entry[K, V], reason EvictReason) {
+	delete(c.items, e.key)
+	c.policy.remove(e)
+	c.cost -= e.cost
+	switch reason {
+	case EvictCapacity:
+		c.stats.Evictions++
+	case EvictExpired:
+		c.stats.Expirations++
+	}
+	c.notify(e.key, e.value, reason)
+}
+
+func (c *cache[K, V]) notify(key K, value V, reason EvictReason) {
+	if c.cfg.OnEvict != nil {
+		c.cfg.OnEvict(key, value, reason)
+	}
+}
+
+func (c *cache[K, V]) Delete(key K) bool {
+	e, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	if c.expired(e, c.cfg.Now()) {
+		c.remove(e, EvictExpired)
+		return false
+	}
+	c.remove(e, EvictDeleted)
+	return true
+}
+
+func (c *cache[K, V]) Purge() int {
+	now := c.cfg.Now()
+	var expired []*entry[K, V]
+	for e := range c.policy.victims() {
+		if c.expired(e, now) {
+			expired = append(expired, e)
+		} else if c.expiryOrdered {
+			break
+		}
+	}
+	for _, e := range expired {
+		c.remove(e, EvictExpired)
+	}
+	return len(expired)
+}
+
+func (c *cache[K, V]) Clear() {
+	items := c.items
+	c.items = make(map[K]*entry[K, V])
+	c.policy.clear()
+	c.cost = 0
+	for _, e := range items {
+		c.notify(e.key, e.value, EvictDeleted)
+	}
+}
+
+func (c *cache[K, V]) Len() int {
+	return len(c.items)
+}
+
+func (c *cache[K, V]) Cost() int64 {
+	return c.cost
+}
+
+func (c *cache[K, V]) Stats() Stats {
+	return c.stats
+}
+
+func (c *cache[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		now := c.cfg.Now()
+		for e := range c.policy.all() {
+			if !c.expired(e, now) && !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
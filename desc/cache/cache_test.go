@@ -0,0 +1,321 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// clock is a manual clock for the TTL tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+type eviction struct {
+	key    string
+	value  int
+	reason EvictReason
+}
+
+// recorder collects the OnEvict calls
+type recorder struct {
+	evictions []eviction
+}
+
+func (r *recorder) OnEvict(key string, value int, reason EvictReason) {
+	r.evictions = append(r.evictions, eviction{key, value, reason})
+}
+
+func keys[V any](c Cache[string, V]) []string {
+	var res []string
+	for k := range c.All() {
+		res = append(res, k)
+	}
+	return res
+}
+
+var constructors = map[string]func(Cfg[string, int]) (Cache[string, int], error){
+	"lru": NewLRU[string, int],
+	"lfu": NewLFU[string, int],
+	"ttl": func(cfg Cfg[string, int]) (Cache[string, int], error) {
+		if cfg.TTL == 0 {
+			cfg.TTL = time.Hour
+		}
+		return NewTTL(cfg)
+	},
+	"sharded": func(cfg Cfg[string, int]) (Cache[string, int], error) {
+		return NewSharded(1, cfg, NewLRU[string, int])
+	},
+}
+
+func TestCache_Common(t *testing.T) {
+	for name, newCache := range constructors {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			c, err := newCache(Cfg[string, int]{MaxEntries: 2, OnEvict: r.OnEvict})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := c.Get("a"); ok {
+				t.Error("Get() on an empty cache should miss")
+			}
+			if !c.Set("a", 1) || !c.Set("b", 2) {
+				t.Fatal("Set() failed")
+			}
+			if v, ok := c.Get("a"); !ok || v != 1 {
+				t.Errorf("Get(a) = %d, %v", v, ok)
+			}
+			if v, ok := c.Peek("b"); !ok || v != 2 {
+				t.Errorf("Peek(b) = %d, %v", v, ok)
+			}
+			if !c.Contains("a") || c.Contains("z") {
+				t.Error("Contains() returned a wrong result")
+			}
+			c.Set("a", 10)
+			if v, _ := c.Peek("a"); v != 10 {
+				t.Errorf("Peek(a) = %d after replacing it", v)
+			}
+			if c.Len() != 2 || c.Cost() != 2 {
+				t.Errorf("Len() = %d, Cost() = %d", c.Len(), c.Cost())
+			}
+			c.Set("c", 3)
+			if c.Len() != 2 {
+				t.Errorf("Len() = %d over MaxEntries", c.Len())
+			}
+			if c.Delete("z") {
+				t.Error("Delete() of a missing key should be false")
+			}
+			if !c.Delete("c") || c.Contains("c") {
+				t.Error("Delete() did not remove the key")
+			}
+			c.Clear()
+			if c.Len() != 0 || c.Cost() != 0 || len(keys(c)) != 0 {
+				t.Error("Clear() left entries")
+			}
+			if st := c.Stats(); st.Hits != 1 || st.Misses != 1 || st.Evictions != 1 {
+				t.Errorf("Stats() = %+v", st)
+			}
+
+			var reasons []EvictReason
+			for _, e := range r.evictions {
+				reasons = append(reasons, e.reason)
+			}
+			want := []EvictReason{EvictReplaced, EvictCapacity, EvictDeleted, EvictDeleted}
+			if !slices.Equal(reasons, want) {
+				t.Errorf("evictions = %v, want %v", reasons, want)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidCfg(t *testing.T) {
+	cfgs := []Cfg[string, int]{
+		{MaxEntries: -1},
+		{MaxCost: -1},
+		{TTL: -time.Second},
+	}
+	for name, newCache := range constructors {
+		for _, cfg := range cfgs {
+			if _, err := newCache(cfg); err != ErrInvalidCfg {
+				t.Errorf("%s(%+v) error = %v, want ErrInvalidCfg", name, cfg, err)
+			}
+		}
+	}
+	if _, err := NewTTL(Cfg[string, int]{}); err != ErrInvalidCfg {
+		t.Errorf("NewTTL() without TTL error = %v, want ErrInvalidCfg", err)
+	}
+}
+
+func TestLRU_Order(t *testing.T) {
+	r := &recorder{}
+	c, _ := NewLRU(Cfg[string, int]{MaxEntries: 3, OnEvict: r.OnEvict})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	// Peek and Contains do not count as a use
+	c.Peek("b")
+	c.Contains("b")
+	if got := keys(c); !slices.Equal(got, []string{"a", "c", "b"}) {
+		t.Errorf("All() = %v", got)
+	}
+	c.Set("d", 4)
+	c.Set("c", 30)
+	c.Set("e", 5)
+	if got := keys(c); !slices.Equal(got, []string{"e", "c", "d"}) {
+		t.Errorf("All() = %v", got)
+	}
+	want := []eviction{{"b", 2, EvictCapacity}, {"c", 3, EvictReplaced}, {"a", 1, EvictCapacity}}
+	if !slices.Equal(r.evictions, want) {
+		t.Errorf("evictions = %v, want %v", r.evictions, want)
+	}
+}
+
+func TestCache_Cost(t *testing.T) {
+	r := &recorder{}
+	c, _ := NewLRU(Cfg[string, int]{
+		MaxCost: 10,
+		Cost:    func(_ string, v int) int64 { return int64(v) },
+		OnEvict: r.OnEvict,
+	})
+	c.Set("a", 4)
+	c.Set("b", 4)
+	if c.Cost() != 8 {
+		t.Errorf("Cost() = %d, want 8", c.Cost())
+	}
+	// growing b pushes out a, but never b itself
+	c.Set("b", 9)
+	if got := keys(c); !slices.Equal(got, []string{"b"}) || c.Cost() != 9 {
+		t.Errorf("All() = %v, Cost() = %d", got, c.Cost())
+	}
+	c.Set("c", 1)
+	c.Set("d", 2)
+	if got := keys(c); !slices.Equal(got, []string{"d", "c"}) || c.Cost() != 3 {
+		t.Errorf("All() = %v, Cost() = %d", got, c.Cost())
+	}
+	// an entry larger than the cache is refused and drops the old value
+	if c.Set("c", 11) {
+		t.Error("Set() of an entry over MaxCost should fail")
+	}
+	if c.Contains("c") || c.Cost() != 2 {
+		t.Errorf("Contains(c) = %v, Cost() = %d", c.Contains("c"), c.Cost())
+	}
+	want := []eviction{
+		{"b", 4, EvictReplaced},
+		{"a", 4, EvictCapacity},
+		{"b", 9, EvictCapacity},
+		{"c", 1, EvictCapacity},
+	}
+	if !slices.Equal(r.evictions, want) {
+		t.Errorf("evictions = %v, want %v", r.evictions, want)
+	}
+	if st := c.Stats(); st.Evictions != 3 {
+		t.Errorf("Evictions = %d, want 3", st.Evictions)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	for name, newCache := range constructors {
+		t.Run(name, func(t *testing.T) {
+			clk := &clock{now: time.Unix(0, 0)}
+			r := &recorder{}
+			c, err := newCache(Cfg[string, int]{TTL: time.Minute, Now: clk.Now, OnEvict: r.OnEvict})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Set("a", 1)
+			clk.Advance(30 * time.Second)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			clk.Advance(30 * time.Second)
+			if c.Contains("a") {
+				t.Error("a should have expired")
+			}
+			if _, ok := c.Peek("a"); ok {
+				t.Error("Peek() returned an expired entry")
+			}
+			if got := keys(c); len(got) != 2 || c.Len() != 3 {
+				t.Errorf("All() = %v, Len() = %d", got, c.Len())
+			}
+			if _, ok := c.Get("a"); ok {
+				t.Error("Get() returned an expired entry")
+			}
+			if c.Len() != 2 {
+				t.Errorf("Get() did not remove the expired entry, Len() = %d", c.Len())
+			}
+			// setting a key again restarts its TTL
+			c.Set("b", 20)
+			clk.Advance(30 * time.Second)
+			if n := c.Purge(); n != 1 {
+				t.Errorf("Purge() = %d, want 1", n)
+			}
+			if got := keys(c); !slices.Equal(got, []string{"b"}) {
+				t.Errorf("All() = %v", got)
+			}
+			clk.Advance(30 * time.Second)
+			if c.Delete("b") {
+				t.Error("Delete() of an expired entry should be false")
+			}
+			c.Set("d", 4)
+			clk.Advance(time.Minute)
+			c.Set("d", 40)
+			if st := c.Stats(); st.Expirations != 4 || st.Misses != 1 {
+				t.Errorf("Stats() = %+v", st)
+			}
+			want := []eviction{
+				{"a", 1, EvictExpired},
+				{"b", 2, EvictReplaced},
+				{"c", 3, EvictExpired},
+				{"b", 20, EvictExpired},
+				{"d", 4, EvictExpired},
+			}
+			if !slices.Equal(r.evictions, want) {
+				t.Errorf("evictions = %v, want %v", r.evictions, want)
+			}
+		})
+	}
+}
+
+func TestTTL_Order(t *testing.T) {
+	clk := &clock{now: time.Unix(0, 0)}
+	r := &recorder{}
+	c, _ := NewTTL(Cfg[string, int]{MaxEntries: 2, TTL: time.Minute, Now: clk.Now, OnEvict: r.OnEvict})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// reads do not protect an entry, writes do
+	c.Get("a")
+	c.Set("c", 3)
+	if got := keys(c); !slices.Equal(got, []string{"c", "b"}) {
+		t.Errorf("All() = %v", got)
+	}
+	c.Set("b", 20)
+	clk.Advance(time.Minute)
+	c.Set("d", 4)
+	if got := keys(c); !slices.Equal(got, []string{"d"}) {
+		t.Errorf("All() = %v", got)
+	}
+	// an expired victim is reported as expired
+	want := []eviction{
+		{"a", 1, EvictCapacity},
+		{"b", 2, EvictReplaced},
+		{"c", 3, EvictExpired},
+	}
+	if !slices.Equal(r.evictions, want) {
+		t.Errorf("evictions = %v, want %v", r.evictions, want)
+	}
+}
+
+func TestStats_HitRatio(t *testing.T) {
+	tests := []struct {
+		stats Stats
+		want  float64
+	}{
+		{Stats{}, 0},
+		{Stats{Hits: 3, Misses: 1}, 0.75},
+		{Stats{Misses: 2}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.stats.HitRatio(); got != tt.want {
+			t.Errorf("%+v.HitRatio() = %v, want %v", tt.stats, got, tt.want)
+		}
+	}
+}
+
+func TestEvictReason_String(t *testing.T) {
+	tests := map[EvictReason]string{
+		EvictCapacity:  "capacity",
+		EvictExpired:   "expired",
+		EvictDeleted:   "deleted",
+		EvictReplaced:  "replaced",
+		EvictReason(9): "unknown",
+	}
+	for r, want := range tests {
+		if got := r.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"iter"
+
+	"github.com/victorwong171/go-utils/desc/list_node"
+)
+
+// lfuBucket holds the entries used freq times, from the most to the least
+// recently used
+type lfuBucket[K comparable, V any] struct {
+	freq    uint64
+	entries list_node.List[*entry[K, V]]
+}
+
+// lfu keeps a list of buckets sorted by increasing frequency, without empty
+// buckets, so every operation is O(1)
+type lfu[K comparable, V any] struct {
+	buckets list_node.List[*lfuBucket[K, V]]
+}
+
+// NewLFU creates a Cache evicting the least frequently used entry first,
+// the least recently used one among equally used entries. Both Get and Set
+// count as a use. The cache is not safe for concurrent use, see NewSharded.
+func NewLFU[K comparable, V any](cfg Cfg[K, V]) (Cache[K, V], error) {
+	return newCache[K, V](cfg, &lfu[K, V]{})
+}
+
+func (p *lfu[K, V]) add(e *entry[K, V]) {
+	front := p.buckets.Front()
+	if front == nil || front.Value.freq != 1 {
+		front = p.buckets.PushFront(&lfuBucket[K, V]{freq: 1})
+	}
+	e.bucket = front
+	e.elem = front.Value.entries.PushFront(e)
+}
+
+func (p *lfu[K, V]) access(e *entry[K, V]) {
+	cur := e.bucket
+	next := cur.Next()
+	if next == nil || next.Value.freq != cur.Value.freq+1 {
+		next = p.buckets.InsertAfter(&lfuBucket[K, V]{freq: cur.Value.freq + 1}, cur)
+	}
+	p.remove(e)
+	e.bucket = next
+	e.elem = next.Value.entries.PushFront(e)
+}
+
+func (p *lfu[K, V]) update(e *entry[K, V]) {
+	p.access(e)
+}
+
+func (p *lfu[K, V]) remove(e *entry[K, V]) {
+	b := e.bucket
+	b.Value.entries.Remove(e.elem)
+	if b.Value.entries.Len() == 0 {
+		p.buckets.Remove(b)
+	}
+	e.elem, e.bucket = nil, nil
+}
+
+func (p *lfu[K, V]) clear() {
+	p.buckets.Clear()
+}
+
+func (p *lfu[K, V]) victims() iter.Seq[*entry[K, V]] {
+	return func(yield func(*entry[K, V]) bool) {
+		for b := range p.buckets.All() {
+			for e := range b.entries.Backward() {
+				if !yield(e) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *lfu[K, V]) all() iter.Seq[*entry[K, V]] {
+	return func(yield func(*entry[K, V]) bool) {
+		for b := range p.buckets.Backward() {
+			for e := range b.entries.All() {
+				if !yield(e) {
+					return
+				}
+			}
+		}
+	}
+}
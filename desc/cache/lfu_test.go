@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLFU_Order(t *testing.T) {
+	r := &recorder{}
+	c, _ := NewLFU(Cfg[string, int]{MaxEntries: 3, OnEvict: r.OnEvict})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Peek("c")
+	if got := keys(c); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("All() = %v", got)
+	}
+	// c is the least frequently used, then d as the newest entry
+	c.Set("d", 4)
+	c.Set("e", 5)
+	if got := keys(c); !slices.Equal(got, []string{"a", "b", "e"}) {
+		t.Errorf("All() = %v", got)
+	}
+	// among equally used entries the least recently used goes first
+	c.Get("e")
+	c.Set("f", 6)
+	if got := keys(c); !slices.Equal(got, []string{"a", "e", "f"}) {
+		t.Errorf("All() = %v", got)
+	}
+	// Set counts as a use
+	c.Set("f", 60)
+	c.Set("f", 61)
+	if got := keys(c); !slices.Equal(got, []string{"f", "a", "e"}) {
+		t.Errorf("All() = %v", got)
+	}
+	var evicted []string
+	for _, e := range r.evictions {
+		if e.reason == EvictCapacity {
+			evicted = append(evicted, e.key)
+		}
+	}
+	if !slices.Equal(evicted, []string{"c", "d", "b"}) {
+		t.Errorf("evicted = %v", evicted)
+	}
+}
+
+func TestLFU_Buckets(t *testing.T) {
+	c, _ := NewLFU(Cfg[string, int]{})
+	p := c.(*cache[string, int]).policy.(*lfu[string, int])
+	freqs := func() []uint64 {
+		var res []uint64
+		for b := range p.buckets.All() {
+			res = append(res, b.freq)
+		}
+		return res
+	}
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Get("a")
+	if got := freqs(); !slices.Equal(got, []uint64{1, 3}) {
+		t.Errorf("freqs = %v, want [1 3]", got)
+	}
+	c.Get("b")
+	c.Get("b")
+	if got := freqs(); !slices.Equal(got, []uint64{3}) {
+		t.Errorf("freqs = %v, want [3]", got)
+	}
+	c.Delete("a")
+	c.Delete("b")
+	if p.buckets.Len() != 0 {
+		t.Errorf("empty buckets left: %v", freqs())
+	}
+}
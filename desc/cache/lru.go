@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"iter"
+
+	"github.com/victorwong171/go-utils/desc/list_node"
+)
+
+// lru keeps the entries from the most to the least recently used
+type lru[K comparable, V any] struct {
+	order list_node.List[*entry[K, V]]
+}
+
+// NewLRU creates a Cache evicting the least recently used entry first. Both
+// Get and Set count as a use. The cache is not safe for concurrent use,
+// see NewSharded.
+func NewLRU[K comparable, V any](cfg Cfg[K, V]) (Cache[K, V], error) {
+	return newCache[K, V](cfg, &lru[K, V]{})
+}
+
+func (p *lru[K, V]) add(e *entry[K, V]) {
+	e.elem = p.order.PushFront(e)
+}
+
+func (p *lru[K, V]) access(e *entry[K, V]) {
+	p.order.MoveToFront(e.elem)
+}
+
+func (p *lru[K, V]) update(e *entry[K, V]) {
+	p.order.MoveToFront(e.elem)
+}
+
+func (p *lru[K, V]) remove(e *entry[K, V]) {
+	p.order.Remove(e.elem)
+	e.elem = nil
+}
+
+func (p *lru[K, V]) clear() {
+	p.order.Clear()
+}
+
+func (p *lru[K, V]) victims() iter.Seq[*entry[K, V]] {
+	return p.order.Backward()
+}
+
+func (p *lru[K, V]) all() iter.Seq[*entry[K, V]] {
+	return p.order.All()
+}
@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"hash/maphash"
+	"iter"
+	"math"
+	"runtime"
+	"sync"
+)
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	c  Cache[K, V]
+}
+
+// sharded spreads keys over independently locked caches
+type sharded[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*shard[K, V]
+}
+
+// NewSharded creates a Cache that is safe for concurrent use by multiple
+// goroutines. Keys are spread over shards caches built by newCache, such as
+// NewLRU, each locked on its own. The bounds of cfg are split between the
+// shards so that they add up to exactly the configured limits; the policy is
+// applied per shard and an entry may cost at most the MaxCost of its shard,
+// about MaxCost/shards. shards is rounded up to a power of two, and it is an
+// ErrInvalidCfg when a bound is smaller than that. When shards is not
+// positive a value derived from GOMAXPROCS is used, lowered if needed so
+// that every shard gets a part of each bound.
+func NewSharded[K comparable, V any](shards int, cfg Cfg[K, V], newCache func(Cfg[K, V]) (Cache[K, V], error)) (Cache[K, V], error) {
+	if cfg.MaxEntries < 0 || cfg.MaxCost < 0 {
+		return nil, ErrInvalidCfg
+	}
+	// every shard needs a positive part of each bound, a 0 meaning no bound
+	limit := int64(math.MaxInt64)
+	if cfg.MaxEntries > 0 {
+		limit = int64(cfg.MaxEntries)
+	}
+	if cfg.MaxCost > 0 {
+		limit = min(limit, cfg.MaxCost)
+	}
+	byDefault := shards <= 0
+	if byDefault {
+		shards = runtime.GOMAXPROCS(0) * 4
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	if int64(n) > limit {
+		if !byDefault {
+			return nil, ErrInvalidCfg
+		}
+		for int64(n) > limit {
+			n >>= 1
+		}
+	}
+	s := &sharded[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*shard[K, V], n),
+	}
+	for i := range s.shards {
+		part := cfg
+		// the first shards take one more entry and unit of cost each, so
+		// that the parts add up to the bounds
+		if cfg.MaxEntries > 0 {
+			part.MaxEntries = cfg.MaxEntries / n
+			if i < cfg.MaxEntries%n {
+				part.MaxEntries++
+			}
+		}
+		if cfg.MaxCost > 0 {
+			part.MaxCost = cfg.MaxCost / int64(n)
+			if int64(i) < cfg.MaxCost%int64(n) {
+				part.MaxCost++
+			}
+		}
+		c, err := newCache(part)
+		if err != nil {
+			return nil, err
+		}
+		s.shards[i] = &shard[K, V]{c: c}
+	}
+	return s, nil
+}
+
+func (s *sharded[K, V]) shardOf(key K) *shard[K, V] {
+	return s.shards[maphash.Comparable(s.seed, key)&uint64(len(s.shards)-1)]
+}
+
+func (s *sharded[K, V]) Get(key K) (V, bool) {
+	sh := s.shardOf(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.c.Get(key)
+}
+
+func (s *sharded[K, V]) Peek(key K) (V, bool) {
+	sh := s.shardOf(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.c.Peek(key)
+}
+
+func (s *sharded[K, V]) Contains(key K) bool {
+	sh := s.shardOf(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	return sh.c.Contains(key)
+}
+
+func (s *sharded[K, V]) Set(key K, value V) bool {
+	sh := s.shardOf(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.c.Set(key, value)
+}
+
+func (s *sharded[K, V]) Delete(key K) bool {
+	sh := s.shardOf(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.c.Delete(key)
+}
+
+func (s *sharded[K, V]) Purge() int {
+	n := 0
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		n += sh.c.Purge()
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+func (s *sharded[K, V]) Clear() {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.c.Clear()
+		sh.mu.Unlock()
+	}
+}
+
+func (s *sharded[K, V]) Len() int {
+	n := 0
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		n += sh.c.Len()
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+func (s *sharded[K, V]) Cost() int64 {
+	var n int64
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		n += sh.c.Cost()
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+func (s *sharded[K, V]) Stats() Stats {
+	var st Stats
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		st = st.add(sh.c.Stats())
+		sh.mu.RUnlock()
+	}
+	return st
+}
+
+// All yields a snapshot of every shard in turn, in the order of its policy;
+// the lock of a shard is not held while its entries are yielded, so the
+// loop body may modify the cache.
+func (s *sharded[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, sh := range s.shards {
+			var (
+				keys   []K
+				values []V
+			)
+			sh.mu.RLock()
+			for k, v := range sh.c.All() {
+				keys = append(keys, k)
+				values = append(values, v)
+			}
+			sh.mu.RUnlock()
+			for i, k := range keys {
+				if !yield(k, values[i]) {
+					return
+				}
+			}
+		}
+	}
+}
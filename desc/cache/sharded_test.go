@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSharded(t *testing.T) {
+	c, err := NewSharded(3, Cfg[string, int]{MaxEntries: 10, MaxCost: 30}, NewLRU[string, int])
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := c.(*sharded[string, int])
+	if len(s.shards) != 4 {
+		t.Errorf("shards = %d, want 4", len(s.shards))
+	}
+	var entries []int
+	var costs []int64
+	for _, sh := range s.shards {
+		cfg := sh.c.(*cache[string, int]).cfg
+		entries = append(entries, cfg.MaxEntries)
+		costs = append(costs, cfg.MaxCost)
+	}
+	if !slices.Equal(entries, []int{3, 3, 2, 2}) || !slices.Equal(costs, []int64{8, 8, 7, 7}) {
+		t.Errorf("shard bounds = %v, %v", entries, costs)
+	}
+
+	for i := range 100 {
+		c.Set(strconv.Itoa(i), i)
+	}
+	if n := c.Len(); n > 10 || n == 0 {
+		t.Errorf("Len() = %d, want at most 10", n)
+	}
+	if c.Cost() != int64(c.Len()) {
+		t.Errorf("Cost() = %d, want %d", c.Cost(), c.Len())
+	}
+	// the loop body may modify the cache
+	n := 0
+	for k, v := range c.All() {
+		if k != strconv.Itoa(v) {
+			t.Errorf("All() yielded %q = %d", k, v)
+		}
+		c.Delete(k)
+		n++
+	}
+	if n == 0 || c.Len() != 0 {
+		t.Errorf("All() yielded %d entries, Len() = %d", n, c.Len())
+	}
+	c.Set("a", 1)
+	for range c.All() {
+		break
+	}
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Clear() left %d entries", c.Len())
+	}
+	if st := c.Stats(); st.Evictions != uint64(100-n) {
+		t.Errorf("Stats() = %+v", st)
+	}
+}
+
+func TestSharded_Defaults(t *testing.T) {
+	c, err := NewSharded(0, Cfg[string, int]{}, NewLFU[string, int])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.(*sharded[string, int]).shards); n == 0 || n&(n-1) != 0 {
+		t.Errorf("shards = %d, want a power of two", n)
+	}
+	if _, err := NewSharded(2, Cfg[string, int]{}, NewTTL[string, int]); err != ErrInvalidCfg {
+		t.Errorf("NewSharded() error = %v, want ErrInvalidCfg", err)
+	}
+	invalid := map[string]Cfg[string, int]{
+		"entries below shards": {MaxEntries: 3},
+		"cost below shards":    {MaxEntries: 10, MaxCost: 2},
+		"negative entries":     {MaxEntries: -1},
+		"negative cost":        {MaxCost: -1},
+	}
+	for name, cfg := range invalid {
+		if _, err := NewSharded(4, cfg, NewLRU[string, int]); err != ErrInvalidCfg {
+			t.Errorf("%s: NewSharded() error = %v, want ErrInvalidCfg", name, err)
+		}
+	}
+}
+
+func TestSharded_Limit(t *testing.T) {
+	// the default shard count is lowered to fit small bounds
+	for _, limit := range []int{1, 2, 3, 5, 7} {
+		c, err := NewSharded(0, Cfg[int, int]{MaxEntries: limit}, NewLRU[int, int])
+		if err != nil {
+			t.Fatalf("MaxEntries = %d: %v", limit, err)
+		}
+		for i := range 1000 {
+			c.Set(i, i)
+		}
+		if n := c.Len(); n > limit {
+			t.Errorf("MaxEntries = %d, Len() = %d", limit, n)
+		}
+	}
+	c, err := NewSharded(0, Cfg[int, int]{
+		MaxCost: 5,
+		Cost:    func(int, int) int64 { return 1 },
+	}, NewLFU[int, int])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 1000 {
+		c.Set(i, i)
+	}
+	if n := c.Cost(); n > 5 {
+		t.Errorf("MaxCost = 5, Cost() = %d", n)
+	}
+}
+
+func TestSharded_Purge(t *testing.T) {
+	clk := &clock{now: time.Unix(0, 0)}
+	c, _ := NewSharded(4, Cfg[string, int]{TTL: time.Minute, Now: clk.Now}, NewTTL[string, int])
+	for i := range 20 {
+		c.Set(strconv.Itoa(i), i)
+	}
+	clk.Advance(time.Minute)
+	if n := c.Purge(); n != 20 {
+		t.Errorf("Purge() = %d, want 20", n)
+	}
+	if st := c.Stats(); st.Expirations != 20 {
+		t.Errorf("Stats() = %+v", st)
+	}
+}
+
+func TestSharded_Race(t *testing.T) {
+	var evicted sync.Map
+	c, _ := NewSharded(8, Cfg[int, int]{
+		MaxEntries: 64,
+		OnEvict: func(key, value int, reason EvictReason) {
+			evicted.Store(key, reason)
+		},
+	}, NewLFU[int, int])
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				k := (g*31 + i) % 200
+				if _, ok := c.Get(k); !ok {
+					c.Set(k, k)
+				}
+				if v, ok := c.Peek(k); ok && v != k {
+					t.Errorf("Peek(%d) = %d", k, v)
+				}
+				if i%100 == 0 {
+					c.Delete(k)
+					c.Contains(k)
+					c.Len()
+					c.Stats()
+					c.Purge()
+					for range c.All() {
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := c.Len(); n > 64 {
+		t.Errorf("Len() = %d over MaxEntries", n)
+	}
+	st := c.Stats()
+	if st.Hits+st.Misses != 8000 {
+		t.Errorf("Hits + Misses = %d, want 8000", st.Hits+st.Misses)
+	}
+}
+
+func BenchmarkSharded(b *testing.B) {
+	for name, newCache := range map[string]func(Cfg[int, int]) (Cache[int, int], error){
+		"lru": NewLRU[int, int],
+		"lfu": NewLFU[int, int],
+	} {
+		b.Run(name, func(b *testing.B) {
+			c, _ := NewSharded(0, Cfg[int, int]{MaxEntries: 1 << 12}, newCache)
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					k := (i * 2654435761) & (1<<13 - 1)
+					if _, ok := c.Get(k); !ok {
+						c.Set(k, i)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
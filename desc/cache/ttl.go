@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"iter"
+
+	"github.com/victorwong171/go-utils/desc/list_node"
+)
+
+// ttl keeps the entries from the least to the most recently set, which is
+// also the order in which they expire
+type ttl[K comparable, V any] struct {
+	order list_node.List[*entry[K, V]]
+}
+
+// NewTTL creates a Cache whose entries expire cfg.TTL after they were last
+// set, which must be positive. When a bound is reached the entry closest to
+// expiry is evicted first, whatever its use, and Purge only visits the
+// expired entries. The cache is not safe for concurrent use, see NewSharded.
+func NewTTL[K comparable, V any](cfg Cfg[K, V]) (Cache[K, V], error) {
+	if cfg.TTL <= 0 {
+		return nil, ErrInvalidCfg
+	}
+	c, err := newCache[K, V](cfg, &ttl[K, V]{})
+	if err != nil {
+		return nil, err
+	}
+	c.expiryOrdered = true
+	return c, nil
+}
+
+func (p *ttl[K, V]) add(e *entry[K, V]) {
+	e.elem = p.order.PushBack(e)
+}
+
+func (p *ttl[K, V]) access(*entry[K, V]) {}
+
+func (p *ttl[K, V]) update(e *entry[K, V]) {
+	p.order.MoveToBack(e.elem)
+}
+
+func (p *ttl[K, V]) remove(e *entry[K, V]) {
+	p.order.Remove(e.elem)
+	e.elem = nil
+}
+
+func (p *ttl[K, V]) clear() {
+	p.order.Clear()
+}
+
+func (p *ttl[K, V]) victims() iter.Seq[*entry[K, V]] {
+	return p.order.All()
+}
+
+func (p *ttl[K, V]) all() iter.Seq[*entry[K, V]] {
+	return p.order.Backward()
+}
@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"github.com/victorwong171/go-utils/utils"
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrVertexNotFound = errors.New("vertex not found")
+	ErrDirected       = errors.New("algorithm needs an undirected graph")
+	ErrUndirected     = errors.New("algorithm needs a directed graph")
+	ErrNegativeWeight = errors.New("negative edge weight")
+	ErrNoPath         = errors.New("no path between vertices")
+	ErrCycle          = errors.New("graph has a cycle")
+)
+
+// Edge is a weighted edge. In an undirected graph From and To are the ends
+// in the order the edge was added.
+type Edge[V comparable, W utils.NUMBER] struct {
+	From, To V
+	Weight   W
+}
+
+type edge[W utils.NUMBER] struct {
+	from, to int
+	weight   W
+}
+
+// Graph is a weighted graph, directed or not, over comparable vertices.
+// Vertices and edges are kept in the order they were added, so every
+// algorithm gives the same answer on every run. Parallel edges and self
+// loops are allowed. A Graph is not safe for concurrent use.
+type Graph[V comparable, W utils.NUMBER] struct {
+	directed bool
+	vertices []V
+	index    map[V]int
+	edges    []edge[W]
+	// adj holds the ids of the edges leaving every vertex, both ends of an
+	// undirected edge list it
+	adj [][]int
+}
+
+// InitGraph returns an empty graph
+func InitGraph[V comparable, W utils.NUMBER](directed bool) *Graph[V, W] {
+	return &Graph[V, W]{directed: directed, index: map[V]int{}}
+}
+
+// Directed reports whether the graph is directed
+func (g *Graph[V, W]) Directed() bool {
+	return g.directed
+}
+
+func (g *Graph[V, W]) idx(v V) int {
+	if i, ok := g.index[v]; ok {
+		return i
+	}
+	i := len(g.vertices)
+	g.index[v] = i
+	g.vertices = append(g.vertices, v)
+	g.adj = append(g.adj, nil)
+	return i
+}
+
+// AddVertex adds the vertices that are not present yet
+func (g *Graph[V, W]) AddVertex(vs ...V) {
+	for _, v := range vs {
+		g.idx(v)
+	}
+}
+
+// AddEdge adds an edge from from to to, adding the vertices if needed
+func (g *Graph[V, W]) AddEdge(from, to V, weight W) {
+	f, t := g.idx(from), g.idx(to)
+	id := len(g.edges)
+	g.edges = append(g.edges, edge[W]{from: f, to: t, weight: weight})
+	g.adj[f] = append(g.adj[f], id)
+	if !g.directed && f != t {
+		g.adj[t] = append(g.adj[t], id)
+	}
+}
+
+// HasVertex reports whether v is in the graph
+func (g *Graph[V, W]) HasVertex(v V) bool {
+	_, ok := g.index[v]
+	return ok
+}
+
+// HasEdge reports whether there is an edge from from to to
+func (g *Graph[V, W]) HasEdge(from, to V) bool {
+	f, ok := g.index[from]
+	t, ok2 := g.index[to]
+	if !ok || !ok2 {
+		return false
+	}
+	for _, id := range g.adj[f] {
+		if g.other(id, f) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// other returns the end of edge id that is not v; for a directed graph,
+// where v is always the start, that is the target
+func (g *Graph[V, W]) other(id, v int) int {
+	e := g.edges[id]
+	if e.from == v {
+		return e.to
+	}
+	return e.from
+}
+
+// Len returns the number of vertices
+func (g *Graph[V, W]) Len() int {
+	return len(g.vertices)
+}
+
+// Vertices returns the vertices in the order they were added
+func (g *Graph[V, W]) Vertices() []V {
+	return append([]V(nil), g.vertices...)
+}
+
+// Edges returns the edges in the order they were added
+func (g *Graph[V, W]) Edges() []Edge[V, W] {
+	res := make([]Edge[V, W], len(g.edges))
+	for i, e := range g.edges {
+		res[i] = g.export(e)
+	}
+	return res
+}
+
+func (g *Graph[V, W]) export(e edge[W]) Edge[V, W] {
+	return Edge[V, W]{From: g.vertices[e.from], To: g.vertices[e.to], Weight: e.weight}
+}
+
+// Neighbors returns the vertices reached by the edges leaving v, once per
+// edge
+func (g *Graph[V, W]) Neighbors(v V) ([]V, error) {
+	i, ok := g.index[v]
+	if !ok {
+		return nil, ErrVertexNotFound
+	}
+	res := make([]V, 0, len(g.adj[i]))
+	for _, id := range g.adj[i] {
+		res = append(res, g.vertices[g.other(id, i)])
+	}
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		directed  bool
+		neighbors map[string][]string
+	}{
+		{name: "directed", directed: true, neighbors: map[string][]string{"a": {"b", "c"}, "b": {"c"}, "c": {}, "d": {"d"}}},
+		{name: "undirected", directed: false, neighbors: map[string][]string{"a": {"b", "c"}, "b": {"a", "c"}, "c": {"b", "a"}, "d": {"d"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := InitGraph[string, int](tt.directed)
+			g.AddVertex("a")
+			g.AddEdge("a", "b", 1)
+			g.AddEdge("b", "c", 2)
+			g.AddEdge("a", "c", 3)
+			g.AddEdge("d", "d", 4)
+			g.AddVertex("a", "d")
+			if g.Directed() != tt.directed || g.Len() != 4 {
+				t.Fatalf("Directed() = %v, Len() = %d", g.Directed(), g.Len())
+			}
+			if got := g.Vertices(); !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+				t.Errorf("Vertices() = %v", got)
+			}
+			want := []Edge[string, int]{{"a", "b", 1}, {"b", "c", 2}, {"a", "c", 3}, {"d", "d", 4}}
+			if got := g.Edges(); !slices.Equal(got, want) {
+				t.Errorf("Edges() = %v, want %v", got, want)
+			}
+			for v, want := range tt.neighbors {
+				if got, err := g.Neighbors(v); err != nil || !slices.Equal(got, want) {
+					t.Errorf("Neighbors(%s) = %v, %v, want %v", v, got, err, want)
+				}
+			}
+			if !g.HasEdge("a", "b") || g.HasEdge("b", "a") != !tt.directed || g.HasEdge("a", "x") {
+				t.Error("HasEdge() is wrong")
+			}
+			if !g.HasVertex("d") || g.HasVertex("x") {
+				t.Error("HasVertex() is wrong")
+			}
+			if _, err := g.Neighbors("x"); !errors.Is(err, ErrVertexNotFound) {
+				t.Errorf("Neighbors() error = %v", err)
+			}
+		})
+	}
+}
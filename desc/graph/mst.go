@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+
+	"github.com/victorwong171/go-utils/desc/union_find"
+	"github.com/victorwong171/go-utils/utils"
+)
+
+// Kruskal returns a minimum spanning forest of an undirected graph and its
+// total weight: a minimum spanning tree of every connected component. Edges
+// of equal weight are considered in the order they were added. It returns
+// ErrDirected for a directed graph.
+func (g *Graph[V, W]) Kruskal() ([]Edge[V, W], W, error) {
+	if g.directed {
+		return nil, 0, ErrDirected
+	}
+	order := make([]int, len(g.edges))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(g.edges[a].weight, g.edges[b].weight)
+	})
+	uf := union_find.InitUnionFind(len(g.vertices))
+	var (
+		res   []Edge[V, W]
+		total W
+	)
+	for _, id := range order {
+		e := g.edges[id]
+		if ok, _ := uf.Connected(e.from, e.to); ok {
+			continue
+		}
+		_ = uf.Union(e.from, e.to)
+		res = append(res, g.export(e))
+		total += e.weight
+		if len(res) == len(g.vertices)-1 {
+			break
+		}
+	}
+	return res, total, nil
+}
+
+// Prim returns a minimum spanning forest of an undirected graph and its
+// total weight, growing a tree from the first vertex of every component.
+// It returns ErrDirected for a directed graph.
+func (g *Graph[V, W]) Prim() ([]Edge[V, W], W, error) {
+	if g.directed {
+		return nil, 0, ErrDirected
+	}
+	inTree := make([]bool, len(g.vertices))
+	var (
+		res   []Edge[V, W]
+		total W
+	)
+	for root := range g.vertices {
+		if inTree[root] {
+			continue
+		}
+		inTree[root] = true
+		pq := &edgeHeap[W]{graph: g.edges}
+		for _, id := range g.adj[root] {
+			heap.Push(pq, id)
+		}
+		for pq.Len() > 0 {
+			id := heap.Pop(pq).(int)
+			e := g.edges[id]
+			v := e.to
+			if inTree[v] {
+				v = e.from
+			}
+			if inTree[v] {
+				continue
+			}
+			inTree[v] = true
+			res = append(res, g.export(e))
+			total += e.weight
+			for _, next := range g.adj[v] {
+				if !inTree[g.other(next, v)] {
+					heap.Push(pq, next)
+				}
+			}
+		}
+	}
+	return res, total, nil
+}
+
+// edgeHeap orders edge ids by weight, then by id
+type edgeHeap[W utils.NUMBER] struct {
+	graph []edge[W]
+	ids   []int
+}
+
+func (h *edgeHeap[W]) Len() int { return len(h.ids) }
+
+func (h *edgeHeap[W]) Less(i, j int) bool {
+	a, b := h.ids[i], h.ids[j]
+	if wa, wb := h.graph[a].weight, h.graph[b].weight; wa != wb {
+		return wa < wb
+	}
+	return a < b
+}
+
+func (h *edgeHeap[W]) Swap(i, j int) { h.ids[i], h.ids[j] = h.ids[j], h.ids[i] }
+
+func (h *edgeHeap[W]) Push(x any) { h.ids = append(h.ids, x.(int)) }
+
+func (h *edgeHeap[W]) Pop() any {
+	x := h.ids[len(h.ids)-1]
+	h.ids = h.ids[:len(h.ids)-1]
+	return x
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestGraph_MinimumSpanningTree(t *testing.T) {
+	g := InitGraph[string, float64](false)
+	for _, e := range []Edge[string, float64]{
+		{"a", "b", 4}, {"a", "h", 8}, {"b", "c", 8}, {"b", "h", 11}, {"c", "d", 7},
+		{"c", "f", 4}, {"c", "i", 2}, {"d", "e", 9}, {"d", "f", 14}, {"e", "f", 10},
+		{"f", "g", 2}, {"g", "h", 1}, {"g", "i", 6}, {"h", "i", 7},
+		// a second component
+		{"x", "y", 3}, {"y", "y", 0},
+	} {
+		g.AddEdge(e.From, e.To, e.Weight)
+	}
+	g.AddVertex("z")
+
+	kruskal, total, err := g.Kruskal()
+	if err != nil || total != 40 || len(kruskal) != 9 {
+		t.Fatalf("Kruskal() = %v, %g, %v", kruskal, total, err)
+	}
+	want := []Edge[string, float64]{
+		{"g", "h", 1}, {"c", "i", 2}, {"f", "g", 2}, {"x", "y", 3}, {"a", "b", 4},
+		{"c", "f", 4}, {"c", "d", 7}, {"a", "h", 8}, {"d", "e", 9},
+	}
+	if !slices.Equal(kruskal, want) {
+		t.Errorf("Kruskal() = %v, want %v", kruskal, want)
+	}
+
+	prim, total, err := g.Prim()
+	if err != nil || total != 40 || len(prim) != 9 {
+		t.Fatalf("Prim() = %v, %g, %v", prim, total, err)
+	}
+
+	d := InitGraph[string, float64](true)
+	if _, _, err := d.Kruskal(); !errors.Is(err, ErrDirected) {
+		t.Errorf("Kruskal() error = %v", err)
+	}
+	if _, _, err := d.Prim(); !errors.Is(err, ErrDirected) {
+		t.Errorf("Prim() error = %v", err)
+	}
+}
+
+// TestGraph_MinimumSpanningRandom checks that Kruskal and Prim agree on the
+// total weight and span the same components
+func TestGraph_MinimumSpanningRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for round := 0; round < 20; round++ {
+		t.Run(fmt.Sprint(round), func(t *testing.T) {
+			const n = 50
+			g := InitGraph[int, int](false)
+			for v := 0; v < n; v++ {
+				g.AddVertex(v)
+			}
+			for i := 0; i < 80; i++ {
+				g.AddEdge(r.IntN(n), r.IntN(n), r.IntN(20))
+			}
+			k, kt, _ := g.Kruskal()
+			p, pt, _ := g.Prim()
+			components := len(g.ConnectedComponents())
+			if kt != pt || len(k) != n-components || len(p) != n-components {
+				t.Errorf("Kruskal %d edges weighing %d, Prim %d edges weighing %d, %d components",
+					len(k), kt, len(p), pt, components)
+			}
+		})
+	}
+}
+
+func BenchmarkGraph_Kruskal(b *testing.B) {
+	g := randomGraph(false, 10000, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = g.Kruskal()
+	}
+}
+
+func BenchmarkGraph_Prim(b *testing.B) {
+	g := randomGraph(false, 10000, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = g.Prim()
+	}
+}
+
+func randomGraph(directed bool, n, m int) *Graph[int, int] {
+	r := rand.New(rand.NewPCG(5, 6))
+	g := InitGraph[int, int](directed)
+	for v := 0; v < n; v++ {
+		g.AddVertex(v)
+	}
+	for i := 0; i < m; i++ {
+		g.AddEdge(r.IntN(n), r.IntN(n), r.IntN(1000))
+	}
+	return g
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"container/heap"
+	"slices"
+
+	"github.com/victorwong171/go-utils/utils"
+)
+
+// Dijkstra returns the length of the shortest path from source to every
+// reachable vertex, and the previous vertex on that path for every
+// reachable vertex but source. It returns ErrNegativeWeight if an edge
+// reachable from source has a negative weight.
+func (g *Graph[V, W]) Dijkstra(source V) (map[V]W, map[V]V, error) {
+	s, ok := g.index[source]
+	if !ok {
+		return nil, nil, ErrVertexNotFound
+	}
+	dist, prev, err := g.dijkstra(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	distances := make(map[V]W, len(dist))
+	previous := make(map[V]V, len(dist))
+	for v, d := range dist {
+		if prev[v] == unreached {
+			continue
+		}
+		distances[g.vertices[v]] = d
+		if v != s {
+			previous[g.vertices[v]] = g.vertices[prev[v]]
+		}
+	}
+	return distances, previous, nil
+}
+
+const unreached = -1
+
+// dijkstra returns the distances from s and the previous vertex of every
+// vertex, unreached for the vertices that cannot be reached and s itself
+// for s
+func (g *Graph[V, W]) dijkstra(s int) ([]W, []int, error) {
+	dist := make([]W, len(g.vertices))
+	prev := make([]int, len(g.vertices))
+	for i := range prev {
+		prev[i] = unreached
+	}
+	prev[s] = s
+	done := make([]bool, len(g.vertices))
+	pq := &distHeap[W]{{vertex: s}}
+	for pq.Len() > 0 {
+		item := heap.Pop(pq).(distItem[W])
+		v := item.vertex
+		// skip entries made stale by a later improvement
+		if done[v] {
+			continue
+		}
+		done[v] = true
+		for _, id := range g.adj[v] {
+			e := g.edges[id]
+			if e.weight < 0 {
+				return nil, nil, ErrNegativeWeight
+			}
+			w := g.other(id, v)
+			if d := dist[v] + e.weight; !done[w] && (prev[w] == unreached || d < dist[w]) {
+				dist[w], prev[w] = d, v
+				heap.Push(pq, distItem[W]{vertex: w, dist: d})
+			}
+		}
+	}
+	return dist, prev, nil
+}
+
+// ShortestPath returns the vertices of a shortest path from source to
+// target, both included, and its length. It returns ErrNoPath if target
+// cannot be reached.
+func (g *Graph[V, W]) ShortestPath(source, target V) ([]V, W, error) {
+	s, ok := g.index[source]
+	t, ok2 := g.index[target]
+	if !ok || !ok2 {
+		return nil, 0, ErrVertexNotFound
+	}
+	dist, prev, err := g.dijkstra(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	if prev[t] == unreached {
+		return nil, 0, ErrNoPath
+	}
+	path := []V{target}
+	for v := t; v != s; v = prev[v] {
+		path = append(path, g.vertices[prev[v]])
+	}
+	slices.Reverse(path)
+	return path, dist[t], nil
+}
+
+type distItem[W utils.NUMBER] struct {
+	vertex int
+	dist   W
+}
+
+type distHeap[W utils.NUMBER] []distItem[W]
+
+func (h distHeap[W]) Len() int { return len(h) }
+
+func (h distHeap[W]) Less(i, j int) bool { return h[i].dist < h[j].dist }
+
+func (h distHeap[W]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *distHeap[W]) Push(x any) { *h = append(*h, x.(distItem[W])) }
+
+func (h *distHeap[W]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
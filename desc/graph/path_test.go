@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestGraph_Dijkstra(t *testing.T) {
+	g := InitGraph[string, int](true)
+	for _, e := range []Edge[string, int]{
+		{"s", "t", 10}, {"s", "y", 5}, {"t", "x", 1}, {"t", "y", 2}, {"y", "t", 3},
+		{"y", "x", 9}, {"y", "z", 2}, {"x", "z", 4}, {"z", "x", 6}, {"z", "s", 7},
+	} {
+		g.AddEdge(e.From, e.To, e.Weight)
+	}
+	g.AddVertex("alone")
+
+	dist, prev, err := g.Dijkstra("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDist := map[string]int{"s": 0, "t": 8, "x": 9, "y": 5, "z": 7}
+	wantPrev := map[string]string{"t": "y", "x": "t", "y": "s", "z": "y"}
+	if !maps.Equal(dist, wantDist) || !maps.Equal(prev, wantPrev) {
+		t.Errorf("Dijkstra() = %v, %v, want %v, %v", dist, prev, wantDist, wantPrev)
+	}
+
+	path, length, err := g.ShortestPath("s", "x")
+	if err != nil || length != 9 || !slices.Equal(path, []string{"s", "y", "t", "x"}) {
+		t.Errorf("ShortestPath() = %v, %d, %v", path, length, err)
+	}
+	if path, length, err = g.ShortestPath("s", "s"); err != nil || length != 0 || !slices.Equal(path, []string{"s"}) {
+		t.Errorf("ShortestPath() to itself = %v, %d, %v", path, length, err)
+	}
+
+	errorTests := []struct {
+		name   string
+		call   func() error
+		target error
+	}{
+		{name: "unreachable", call: func() error { _, _, err := g.ShortestPath("s", "alone"); return err }, target: ErrNoPath},
+		{name: "missing target", call: func() error { _, _, err := g.ShortestPath("s", "nope"); return err }, target: ErrVertexNotFound},
+		{name: "missing source", call: func() error { _, _, err := g.Dijkstra("nope"); return err }, target: ErrVertexNotFound},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.target) {
+				t.Errorf("error = %v, want %v", err, tt.target)
+			}
+		})
+	}
+
+	g.AddEdge("x", "alone", -1)
+	if _, _, err := g.Dijkstra("s"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Dijkstra() error = %v, want %v", err, ErrNegativeWeight)
+	}
+	if _, _, err := g.ShortestPath("s", "x"); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("ShortestPath() error = %v, want %v", err, ErrNegativeWeight)
+	}
+}
+
+// bellmanFord is the simple O(VE) reference for shortest distances
+func bellmanFord(g *Graph[int, int], s int) map[int]int {
+	dist := map[int]int{s: 0}
+	for range g.vertices {
+		for _, e := range g.Edges() {
+			relax := func(from, to int) {
+				if d, ok := dist[from]; ok {
+					if old, ok := dist[to]; !ok || d+e.Weight < old {
+						dist[to] = d + e.Weight
+					}
+				}
+			}
+			relax(e.From, e.To)
+			if !g.directed {
+				relax(e.To, e.From)
+			}
+		}
+	}
+	return dist
+}
+
+func TestGraph_DijkstraRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	for round := 0; round < 20; round++ {
+		t.Run(fmt.Sprint(round), func(t *testing.T) {
+			g := InitGraph[int, int](round%2 == 0)
+			for i := 0; i < 120; i++ {
+				g.AddEdge(r.IntN(40), r.IntN(40), r.IntN(50))
+			}
+			s := g.Vertices()[0]
+			dist, prev, err := g.Dijkstra(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bellmanFord(g, s); !maps.Equal(dist, want) {
+				t.Fatalf("Dijkstra() = %v, want %v", dist, want)
+			}
+			for v, p := range prev {
+				if dist[p] > dist[v] {
+					t.Fatalf("previous vertex %d of %d is farther away", p, v)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGraph_Dijkstra(b *testing.B) {
+	g := randomGraph(true, 10000, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = g.Dijkstra(i % 10000)
+	}
+}
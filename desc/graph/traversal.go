@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/victorwong171/go-utils/desc/union_find"
+)
+
+// BFS returns an iterator over the vertices reachable from start in breadth
+// first order. It yields nothing if start is not in the graph.
+func (g *Graph[V, W]) BFS(start V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		s, ok := g.index[start]
+		if !ok {
+			return
+		}
+		seen := make([]bool, len(g.vertices))
+		seen[s] = true
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if !yield(g.vertices[v]) {
+				return
+			}
+			for _, id := range g.adj[v] {
+				if w := g.other(id, v); !seen[w] {
+					seen[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+	}
+}
+
+// DFS returns an iterator over the vertices reachable from start in depth
+// first preorder, neighbors being visited in edge order. It yields nothing
+// if start is not in the graph.
+func (g *Graph[V, W]) DFS(start V) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		s, ok := g.index[start]
+		if !ok {
+			return
+		}
+		seen := make([]bool, len(g.vertices))
+		stack := []int{s}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			if !yield(g.vertices[v]) {
+				return
+			}
+			// push in reverse so that the first edge is explored first
+			for i := len(g.adj[v]) - 1; i >= 0; i-- {
+				if w := g.other(g.adj[v][i], v); !seen[w] {
+					stack = append(stack, w)
+				}
+			}
+		}
+	}
+}
+
+// ConnectedComponents returns the connected components, ignoring edge
+// directions, so for a directed graph these are the weakly connected ones.
+// Components are ordered by their first vertex, vertices by insertion.
+func (g *Graph[V, W]) ConnectedComponents() [][]V {
+	uf := union_find.InitUnionFind(len(g.vertices))
+	for _, e := range g.edges {
+		_ = uf.Union(e.from, e.to)
+	}
+	var res [][]V
+	component := make(map[int]int, uf.Count())
+	for v := range g.vertices {
+		root, _ := uf.Find(v)
+		c, ok := component[root]
+		if !ok {
+			c = len(res)
+			component[root] = c
+			res = append(res, nil)
+		}
+		res[c] = append(res[c], g.vertices[v])
+	}
+	return res
+}
+
+// HasCycle reports whether the graph has a cycle. In an undirected graph a
+// self loop or two parallel edges form a cycle.
+func (g *Graph[V, W]) HasCycle() bool {
+	if !g.directed {
+		uf := union_find.InitUnionFind(len(g.vertices))
+		for _, e := range g.edges {
+			if ok, _ := uf.Connected(e.from, e.to); ok {
+				return true
+			}
+			_ = uf.Union(e.from, e.to)
+		}
+		return false
+	}
+	_, err := g.TopologicalSort()
+	return err != nil
+}
+
+// TopologicalSort orders the vertices of a directed acyclic graph so that
+// every edge points forward. Among the vertices that are ready at the same
+// time the earliest added comes first. It returns ErrCycle if there is a
+// cycle and ErrUndirected for an undirected graph.
+func (g *Graph[V, W]) TopologicalSort() ([]V, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+	indegree := make([]int, len(g.vertices))
+	for _, e := range g.edges {
+		indegree[e.to]++
+	}
+	// ready holds the vertices without pending edges sorted by index
+	var ready []int
+	for v, d := range indegree {
+		if d == 0 {
+			ready = append(ready, v)
+		}
+	}
+	res := make([]V, 0, len(g.vertices))
+	for len(ready) > 0 {
+		v := ready[0]
+		ready = ready[1:]
+		res = append(res, g.vertices[v])
+		for _, id := range g.adj[v] {
+			w := g.edges[id].to
+			if indegree[w]--; indegree[w] == 0 {
+				i, _ := slices.BinarySearch(ready, w)
+				ready = slices.Insert(ready, i, w)
+			}
+		}
+	}
+	if len(res) != len(g.vertices) {
+		return nil, ErrCycle
+	}
+	return res, nil
+}
+
+// StronglyConnectedComponents returns the strongly connected components of
+// a directed graph with Tarjan's algorithm, in reverse topological order of
+// the condensed graph: no edge leads from a component to an earlier one.
+// For an undirected graph it returns the connected components.
+func (g *Graph[V, W]) StronglyConnectedComponents() [][]V {
+	if !g.directed {
+		return g.ConnectedComponents()
+	}
+	const unvisited = -1
+	n := len(g.vertices)
+	index, low := make([]int, n), make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = unvisited
+	}
+	var (
+		stack []int
+		res   [][]V
+		next  int
+	)
+	// frame is a vertex being explored and the position in its edge list,
+	// the recursion is unrolled so that long paths cannot exhaust the stack
+	type frame struct{ v, edge int }
+	for root := range g.vertices {
+		if index[root] != unvisited {
+			continue
+		}
+		calls := []frame{{v: root}}
+		index[root], low[root] = next, next
+		next++
+		stack = append(stack, root)
+		onStack[root] = true
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			v := f.v
+			if f.edge < len(g.adj[v]) {
+				w := g.edges[g.adj[v][f.edge]].to
+				f.edge++
+				switch {
+				case index[w] == unvisited:
+					index[w], low[w] = next, next
+					next++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{v: w})
+				case onStack[w]:
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].v
+				low[parent] = min(low[parent], low[v])
+			}
+			if low[v] == index[v] {
+				var component []V
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, g.vertices[w])
+					if w == v {
+						break
+					}
+				}
+				slices.Reverse(component)
+				res = append(res, component)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func graphOf(directed bool, edges ...[2]int) *Graph[int, int] {
+	g := InitGraph[int, int](directed)
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	return g
+}
+
+func TestGraph_BFSAndDFS(t *testing.T) {
+	//   1 - 2 - 4
+	//   |   |
+	//   3 - 5   6 - 7
+	g := graphOf(false, [2]int{1, 2}, [2]int{1, 3}, [2]int{2, 4}, [2]int{2, 5}, [2]int{3, 5}, [2]int{6, 7})
+	if got := slices.Collect(g.BFS(1)); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("BFS() = %v", got)
+	}
+	if got := slices.Collect(g.DFS(1)); !slices.Equal(got, []int{1, 2, 4, 5, 3}) {
+		t.Errorf("DFS() = %v", got)
+	}
+	if got := slices.Collect(g.BFS(9)); got != nil {
+		t.Errorf("BFS() of a missing vertex = %v", got)
+	}
+	if got := slices.Collect(g.DFS(9)); got != nil {
+		t.Errorf("DFS() of a missing vertex = %v", got)
+	}
+	for _, seq := range []func(int) iter.Seq[int]{g.BFS, g.DFS} {
+		n := 0
+		for range seq(1) {
+			n++
+			if n == 2 {
+				break
+			}
+		}
+	}
+
+	d := graphOf(true, [2]int{1, 2}, [2]int{3, 1})
+	if got := slices.Collect(d.BFS(1)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("directed BFS() = %v", got)
+	}
+}
+
+func TestGraph_ConnectedComponents(t *testing.T) {
+	g := graphOf(true, [2]int{1, 2}, [2]int{3, 4}, [2]int{4, 2}, [2]int{5, 6})
+	g.AddVertex(7)
+	want := [][]int{{1, 2, 3, 4}, {5, 6}, {7}}
+	if got := g.ConnectedComponents(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("ConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_HasCycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		directed bool
+		edges    [][2]int
+		want     bool
+	}{
+		{name: "undirected tree", edges: [][2]int{{1, 2}, {2, 3}, {2, 4}}, want: false},
+		{name: "undirected triangle", edges: [][2]int{{1, 2}, {2, 3}, {3, 1}}, want: true},
+		{name: "undirected parallel edges", edges: [][2]int{{1, 2}, {2, 1}}, want: true},
+		{name: "directed diamond", directed: true, edges: [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}}, want: false},
+		{name: "directed triangle", directed: true, edges: [][2]int{{1, 2}, {2, 3}, {3, 1}}, want: true},
+		{name: "directed self loop", directed: true, edges: [][2]int{{1, 1}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graphOf(tt.directed, tt.edges...).HasCycle(); got != tt.want {
+				t.Errorf("HasCycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_TopologicalSort(t *testing.T) {
+	g := InitGraph[string, int](true)
+	g.AddVertex("shirt", "tie", "jacket", "belt", "trousers", "shoes", "socks")
+	for _, e := range [][2]string{
+		{"shirt", "tie"}, {"tie", "jacket"}, {"shirt", "belt"}, {"belt", "jacket"},
+		{"trousers", "belt"}, {"trousers", "shoes"}, {"socks", "shoes"},
+	} {
+		g.AddEdge(e[0], e[1], 0)
+	}
+	want := []string{"shirt", "tie", "trousers", "belt", "jacket", "socks", "shoes"}
+	if got, err := g.TopologicalSort(); err != nil || !slices.Equal(got, want) {
+		t.Errorf("TopologicalSort() = %v, %v, want %v", got, err, want)
+	}
+
+	g.AddEdge("jacket", "shirt", 0)
+	if _, err := g.TopologicalSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalSort() error = %v, want %v", err, ErrCycle)
+	}
+	if _, err := graphOf(false).TopologicalSort(); !errors.Is(err, ErrUndirected) {
+		t.Errorf("TopologicalSort() error = %v, want %v", err, ErrUndirected)
+	}
+}
+
+func TestGraph_StronglyConnectedComponents(t *testing.T) {
+	g := graphOf(true,
+		[2]int{1, 2}, [2]int{2, 3}, [2]int{3, 1},
+		[2]int{3, 4}, [2]int{4, 5}, [2]int{5, 4},
+		[2]int{6, 5}, [2]int{6, 6},
+	)
+	want := [][]int{{4, 5}, {1, 2, 3}, {6}}
+	if got := g.StronglyConnectedComponents(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+
+	u := graphOf(false, [2]int{1, 2}, [2]int{3, 4})
+	want = [][]int{{1, 2}, {3, 4}}
+	if got := u.StronglyConnectedComponents(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("undirected StronglyConnectedComponents() = %v, want %v", got, want)
+	}
+}
+
+// TestGraph_StronglyConnectedRandom checks Tarjan against mutual
+// reachability and the promised component order on random graphs
+func TestGraph_StronglyConnectedRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for round := 0; round < 20; round++ {
+		t.Run(fmt.Sprint(round), func(t *testing.T) {
+			const n = 30
+			g := InitGraph[int, int](true)
+			for v := 0; v < n; v++ {
+				g.AddVertex(v)
+			}
+			for i := 0; i < 45; i++ {
+				g.AddEdge(r.IntN(n), r.IntN(n), 1)
+			}
+			reach := make([][]bool, n)
+			for v := range reach {
+				reach[v] = make([]bool, n)
+				for w := range g.BFS(v) {
+					reach[v][w] = true
+				}
+			}
+			component := make([]int, n)
+			for c, vs := range g.StronglyConnectedComponents() {
+				for _, v := range vs {
+					component[v] = c
+				}
+			}
+			for v := 0; v < n; v++ {
+				for w := 0; w < n; w++ {
+					if same := reach[v][w] && reach[w][v]; same != (component[v] == component[w]) {
+						t.Fatalf("%d and %d: mutually reachable %v, same component %v", v, w, same, !same)
+					}
+					if g.HasEdge(v, w) && component[v] < component[w] {
+						t.Fatalf("edge %d -> %d leads to a later component", v, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGraph_LongPath(t *testing.T) {
+	const n = 100000
+	g := InitGraph[int, int](true)
+	for v := 1; v < n; v++ {
+		g.AddEdge(v-1, v, 1)
+	}
+	g.AddEdge(n-1, 0, 1)
+	if got := g.StronglyConnectedComponents(); len(got) != 1 || len(got[0]) != n {
+		t.Errorf("found %d components", len(got))
+	}
+}
@@ -0,0 +1,204 @@
+package list_node
+
+import (
+	"iter"
+)
+
+// Element is an element of a List
+type Element[T any] struct {
+	Value T
+	// the list is a ring around the sentinel root of its List
+	next, prev *Element[T]
+	list       *List[T]
+}
+
+// Next returns the next element or nil
+func (e *Element[T]) Next() *Element[T] {
+	if n := e.next; e.list != nil && n != &e.list.root {
+		return n
+	}
+	return nil
+}
+
+// Prev returns the previous element or nil
+func (e *Element[T]) Prev() *Element[T] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// List is a doubly linked list, a generic container/list. The zero value
+// is an empty list ready to use. Methods taking an element do nothing if it
+// belongs to another list. A List is not safe for concurrent use.
+type List[T any] struct {
+	root Element[T]
+	len  int
+}
+
+// InitList returns an empty list
+func InitList[T any]() *List[T] {
+	return new(List[T]).Clear()
+}
+
+// Clear removes every element and returns l. The removed elements are
+// detached, so later calls with them leave l untouched.
+func (l *List[T]) Clear() *List[T] {
+	for e := l.root.next; e != nil && e != &l.root; {
+		next := e.next
+		e.next, e.prev, e.list = nil, nil, nil
+		e = next
+	}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+	return l
+}
+
+func (l *List[T]) lazyInit() {
+	if l.root.next == nil {
+		l.Clear()
+	}
+}
+
+// Len returns the number of elements
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front returns the first element or nil
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last element or nil
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// insert puts e after at
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.len++
+	return e
+}
+
+// unlink takes e out of the ring without touching its list
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// move moves e after at
+func (l *List[T]) move(e, at *Element[T]) {
+	if e == at {
+		return
+	}
+	l.unlink(e)
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// PushFront inserts v at the front and returns its element
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, &l.root)
+}
+
+// PushBack inserts v at the back and returns its element
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, l.root.prev)
+}
+
+// InsertBefore inserts v before mark and returns its element, or nil if
+// mark is not in l
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark.prev)
+}
+
+// InsertAfter inserts v after mark and returns its element, or nil if mark
+// is not in l
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark)
+}
+
+// Remove removes e if it is in l and returns its value
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.unlink(e)
+		// avoid memory leaks and mark e as removed
+		e.next, e.prev, e.list = nil, nil, nil
+		l.len--
+	}
+	return e.Value
+}
+
+// MoveToFront moves e to the front
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	l.move(e, &l.root)
+}
+
+// MoveToBack moves e to the back
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list != l || l.root.prev == e {
+		return
+	}
+	l.move(e, l.root.prev)
+}
+
+// MoveAfter moves e after mark
+func (l *List[T]) MoveAfter(e, mark *Element[T]) {
+	if e.list != l || mark.list != l || e == mark {
+		return
+	}
+	l.move(e, mark)
+}
+
+// All returns an iterator over the values from front to back. The current
+// element may be removed during the iteration.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Front(); e != nil; {
+			next := e.Next()
+			if !yield(e.Value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward returns an iterator over the values from back to front
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Back(); e != nil; {
+			prev := e.Prev()
+			if !yield(e.Value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
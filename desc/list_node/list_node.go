@@ -1,23 +1,143 @@
 package list_node
 
-type ListNode struct {
-	Val  int
-	Next *ListNode
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// ListNode is a node of a singly linked list, the list being its head. A nil
+// *ListNode is the empty list, and every helper accepts it.
+type ListNode[T any] struct {
+	Val  T
+	Next *ListNode[T]
 }
 
-func ListNodeify(list ...int) *ListNode {
+// ListNodeify builds a list holding the values of list in order
+func ListNodeify[T any](list ...T) *ListNode[T] {
 	if len(list) == 0 {
 		return nil
 	}
-	head := &ListNode{
+	head := &ListNode[T]{
 		Val: list[0],
 	}
 	curr := head
 	for i := 1; i < len(list); i++ {
-		curr.Next = &ListNode{
+		curr.Next = &ListNode[T]{
 			Val: list[i],
 		}
 		curr = curr.Next
 	}
 	return head
 }
+
+// ToSlice returns the values of the list in order
+func (l *ListNode[T]) ToSlice() []T {
+	var res []T
+	for n := l; n != nil; n = n.Next {
+		res = append(res, n.Val)
+	}
+	return res
+}
+
+// Reverse reverses the list in place and returns the new head
+func (l *ListNode[T]) Reverse() *ListNode[T] {
+	var prev *ListNode[T]
+	for curr := l; curr != nil; {
+		next := curr.Next
+		curr.Next = prev
+		prev, curr = curr, next
+	}
+	return prev
+}
+
+// Middle returns the middle node, the second of the two middle nodes for an
+// even length
+func (l *ListNode[T]) Middle() *ListNode[T] {
+	slow, fast := l, l
+	for fast != nil && fast.Next != nil {
+		slow, fast = slow.Next, fast.Next.Next
+	}
+	return slow
+}
+
+// HasCycle reports whether following Next ever comes back to a node, using
+// Floyd's tortoise and hare in constant space
+func (l *ListNode[T]) HasCycle() bool {
+	slow, fast := l, l
+	for fast != nil && fast.Next != nil {
+		slow, fast = slow.Next, fast.Next.Next
+		if slow == fast {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveNthFromEnd removes the n-th node counted from the end, 1 being the
+// last one, and returns the new head. The list is unchanged if n is out of
+// range.
+func (l *ListNode[T]) RemoveNthFromEnd(n int) *ListNode[T] {
+	if n <= 0 {
+		return l
+	}
+	dummy := &ListNode[T]{Next: l}
+	fast := dummy
+	for i := 0; i < n; i++ {
+		if fast.Next == nil {
+			return l
+		}
+		fast = fast.Next
+	}
+	slow := dummy
+	for fast.Next != nil {
+		slow, fast = slow.Next, fast.Next
+	}
+	slow.Next = slow.Next.Next
+	return dummy.Next
+}
+
+// String formats the list as "1 -> 2 -> 3". A cycle is cut after its first
+// round and marked with "...".
+func (l *ListNode[T]) String() string {
+	var sb strings.Builder
+	seen := map[*ListNode[T]]bool{}
+	for n := l; n != nil; n = n.Next {
+		if sb.Len() > 0 {
+			sb.WriteString(" -> ")
+		}
+		if seen[n] {
+			sb.WriteString("...")
+			break
+		}
+		seen[n] = true
+		fmt.Fprint(&sb, n.Val)
+	}
+	return sb.String()
+}
+
+// MergeSorted merges two ascending lists into one ascending list, reusing
+// their nodes. Equal values from a come first.
+func MergeSorted[T cmp.Ordered](a, b *ListNode[T]) *ListNode[T] {
+	return MergeSortedFunc(a, b, cmp.Compare[T])
+}
+
+// MergeSortedFunc is MergeSorted with the order given by cmp
+func MergeSortedFunc[T any](a, b *ListNode[T], cmp func(x, y T) int) *ListNode[T] {
+	dummy := &ListNode[T]{}
+	tail := dummy
+	for a != nil && b != nil {
+		if cmp(b.Val, a.Val) < 0 {
+			tail.Next, b = b, b.Next
+		} else {
+			tail.Next, a = a, a.Next
+		}
+		tail = tail.Next
+	}
+	if a != nil {
+		tail.Next = a
+	} else {
+		tail.Next = b
+	}
+	return dummy.Next
+}
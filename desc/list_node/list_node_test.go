@@ -2,6 +2,7 @@ package list_node
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -12,22 +13,22 @@ func TestListNodeify(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want *ListNode
+		want *ListNode[int]
 	}{
 		{
 			name: "all is ok",
 			args: args{
 				list: []int{1, 2, 3, 4, 5},
 			},
-			want: &ListNode{
+			want: &ListNode[int]{
 				Val: 1,
-				Next: &ListNode{
+				Next: &ListNode[int]{
 					Val: 2,
-					Next: &ListNode{
+					Next: &ListNode[int]{
 						Val: 3,
-						Next: &ListNode{
+						Next: &ListNode[int]{
 							Val: 4,
-							Next: &ListNode{
+							Next: &ListNode[int]{
 								Val:  5,
 								Next: nil,
 							},
@@ -52,3 +53,146 @@ func TestListNodeify(t *testing.T) {
 		})
 	}
 }
+
+func TestListNode_ToSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+	}{
+		{name: "nil list", list: nil},
+		{name: "one node", list: []int{1}},
+		{name: "many nodes", list: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ListNodeify(tt.list...).ToSlice(); !slices.Equal(got, tt.list) {
+				t.Errorf("ToSlice() = %v, want %v", got, tt.list)
+			}
+		})
+	}
+}
+
+func TestListNode_Reverse(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+		want []int
+	}{
+		{name: "nil list", list: nil, want: nil},
+		{name: "one node", list: []int{1}, want: []int{1}},
+		{name: "many nodes", list: []int{1, 2, 3, 4}, want: []int{4, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ListNodeify(tt.list...).Reverse().ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("Reverse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListNode_Middle(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+		want []int
+	}{
+		{name: "nil list", list: nil, want: nil},
+		{name: "odd length", list: []int{1, 2, 3, 4, 5}, want: []int{3, 4, 5}},
+		{name: "even length", list: []int{1, 2, 3, 4}, want: []int{3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ListNodeify(tt.list...).Middle().ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("Middle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListNode_HasCycle(t *testing.T) {
+	cycle := ListNodeify(1, 2, 3, 4)
+	cycle.Next.Next.Next.Next = cycle.Next
+	self := ListNodeify(1)
+	self.Next = self
+	tests := []struct {
+		name string
+		head *ListNode[int]
+		want bool
+		str  string
+	}{
+		{name: "nil list", head: nil, want: false, str: ""},
+		{name: "no cycle", head: ListNodeify(1, 2, 3), want: false, str: "1 -> 2 -> 3"},
+		{name: "cycle", head: cycle, want: true, str: "1 -> 2 -> 3 -> 4 -> ..."},
+		{name: "self loop", head: self, want: true, str: "1 -> ..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.head.HasCycle(); got != tt.want {
+				t.Errorf("HasCycle() = %v, want %v", got, tt.want)
+			}
+			if got := tt.head.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+		})
+	}
+}
+
+func TestListNode_RemoveNthFromEnd(t *testing.T) {
+	tests := []struct {
+		name string
+		list []int
+		n    int
+		want []int
+	}{
+		{name: "last", list: []int{1, 2, 3}, n: 1, want: []int{1, 2}},
+		{name: "middle", list: []int{1, 2, 3}, n: 2, want: []int{1, 3}},
+		{name: "head", list: []int{1, 2, 3}, n: 3, want: []int{2, 3}},
+		{name: "only node", list: []int{1}, n: 1, want: nil},
+		{name: "too far", list: []int{1, 2, 3}, n: 4, want: []int{1, 2, 3}},
+		{name: "zero", list: []int{1, 2, 3}, n: 0, want: []int{1, 2, 3}},
+		{name: "nil list", list: nil, n: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ListNodeify(tt.list...).RemoveNthFromEnd(tt.n).ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("RemoveNthFromEnd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		want []int
+	}{
+		{name: "both nil", a: nil, b: nil, want: nil},
+		{name: "a nil", a: nil, b: []int{1, 2}, want: []int{1, 2}},
+		{name: "b nil", a: []int{1, 2}, b: nil, want: []int{1, 2}},
+		{name: "interleaved", a: []int{1, 3, 5}, b: []int{2, 4, 6, 8}, want: []int{1, 2, 3, 4, 5, 6, 8}},
+		{name: "duplicates", a: []int{1, 2, 2}, b: []int{2, 3}, want: []int{1, 2, 2, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeSorted(ListNodeify(tt.a...), ListNodeify(tt.b...)).ToSlice(); !slices.Equal(got, tt.want) {
+				t.Errorf("MergeSorted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSortedFunc_Stable(t *testing.T) {
+	type pair struct {
+		key  int
+		from string
+	}
+	a := ListNodeify(pair{1, "a"}, pair{2, "a"})
+	b := ListNodeify(pair{1, "b"}, pair{2, "b"})
+	got := MergeSortedFunc(a, b, func(x, y pair) int { return x.key - y.key }).ToSlice()
+	want := []pair{{1, "a"}, {1, "b"}, {2, "a"}, {2, "b"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeSortedFunc() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package list_node
+
+import (
+	"slices"
+	"testing"
+)
+
+func checkList[T comparable](t *testing.T, l *List[T], want []T) {
+	t.Helper()
+	if l.Len() != len(want) {
+		t.Errorf("Len() = %d, want %d", l.Len(), len(want))
+	}
+	if got := slices.Collect(l.All()); !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	backward := slices.Clone(want)
+	slices.Reverse(backward)
+	if got := slices.Collect(l.Backward()); !slices.Equal(got, backward) {
+		t.Errorf("Backward() = %v, want %v", got, backward)
+	}
+	// the links must agree in both directions
+	var prev *Element[T]
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.Prev() != prev {
+			t.Fatalf("Prev() of %v is inconsistent", e.Value)
+		}
+		prev = e
+	}
+	if l.Back() != prev {
+		t.Errorf("Back() is not the last element")
+	}
+}
+
+func TestList(t *testing.T) {
+	l := InitList[int]()
+	checkList(t, l, nil)
+	if l.Front() != nil || l.Back() != nil {
+		t.Fatal("empty list has elements")
+	}
+
+	e2 := l.PushBack(2)
+	e1 := l.PushFront(1)
+	e3 := l.PushBack(3)
+	checkList(t, l, []int{1, 2, 3})
+
+	l.MoveToFront(e3)
+	checkList(t, l, []int{3, 1, 2})
+	l.MoveToFront(e3)
+	checkList(t, l, []int{3, 1, 2})
+	l.MoveToBack(e3)
+	checkList(t, l, []int{1, 2, 3})
+	l.MoveToBack(e3)
+	checkList(t, l, []int{1, 2, 3})
+	l.MoveAfter(e1, e2)
+	checkList(t, l, []int{2, 1, 3})
+	l.MoveAfter(e1, e1)
+	checkList(t, l, []int{2, 1, 3})
+
+	e4 := l.InsertBefore(4, e1)
+	l.InsertAfter(5, e1)
+	checkList(t, l, []int{2, 4, 1, 5, 3})
+
+	if v := l.Remove(e4); v != 4 {
+		t.Errorf("Remove() = %d, want 4", v)
+	}
+	checkList(t, l, []int{2, 1, 5, 3})
+	// a removed element is no longer part of l
+	l.Remove(e4)
+	l.MoveToFront(e4)
+	checkList(t, l, []int{2, 1, 5, 3})
+	if e4.Next() != nil || e4.Prev() != nil {
+		t.Error("removed element still linked")
+	}
+
+	front := l.Front()
+	l.Clear()
+	checkList(t, l, nil)
+	// the cleared elements no longer belong to l
+	l.Remove(front)
+	l.MoveToFront(front)
+	if l.InsertAfter(7, front) != nil {
+		t.Error("inserted next to a cleared element")
+	}
+	checkList(t, l, nil)
+	l.PushBack(8)
+	l.Remove(front)
+	checkList(t, l, []int{8})
+}
+
+func TestList_ZeroValue(t *testing.T) {
+	var l List[string]
+	checkList(t, &l, nil)
+	l.PushFront("b")
+	l.PushFront("a")
+	checkList(t, &l, []string{"a", "b"})
+
+	var other List[string]
+	l.PushBack("c")
+	e := other.PushBack("x")
+	l.MoveToFront(e)
+	l.Remove(e)
+	if l.InsertBefore("y", e) != nil || l.InsertAfter("y", e) != nil {
+		t.Error("inserted next to an element of another list")
+	}
+	checkList(t, &l, []string{"a", "b", "c"})
+	checkList(t, &other, []string{"x"})
+}
+
+func TestList_RemoveWhileIterating(t *testing.T) {
+	l := InitList[int]()
+	for i := range 6 {
+		l.PushBack(i)
+	}
+	e := l.Front()
+	for v := range l.All() {
+		next := e.Next()
+		if v%2 == 0 {
+			l.Remove(e)
+		}
+		e = next
+	}
+	checkList(t, l, []int{1, 3, 5})
+
+	for v := range l.All() {
+		if v == 3 {
+			break
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package set
+
+// Union returns a new set holding every key of sets, backed by the
+// implementation of the first operand
+func Union[K comparable](sets ...Set[K]) Set[K] {
+	if len(sets) == 0 {
+		return InitSet[K](0)
+	}
+	res := sets[0].Clone()
+	for _, s := range sets[1:] {
+		res.UnionWith(s)
+	}
+	return res
+}
+
+// Intersect returns a new set holding the keys present in all of sets
+func Intersect[K comparable](sets ...Set[K]) Set[K] {
+	if len(sets) == 0 {
+		return InitSet[K](0)
+	}
+	res := sets[0].Clone()
+	for _, s := range sets[1:] {
+		res.IntersectWith(s)
+	}
+	return res
+}
+
+// Difference returns the keys of a that are not in b
+func Difference[K comparable](a, b Set[K]) Set[K] {
+	return a.Difference(b)
+}
+
+// SymmetricDifference returns the keys that are in exactly one of a and b
+func SymmetricDifference[K comparable](a, b Set[K]) Set[K] {
+	return a.SymmetricDifference(b)
+}
+
+// IsSubset reports whether every key of a is in b
+func IsSubset[K comparable](a, b Set[K]) bool {
+	return isSubset(a, b)
+}
+
+// IsSuperset reports whether every key of b is in a
+func IsSuperset[K comparable](a, b Set[K]) bool {
+	return isSubset(b, a)
+}
+
+// Equal reports whether a and b hold the same keys
+func Equal[K comparable](a, b Set[K]) bool {
+	return a.Len() == b.Len() && isSubset(a, b)
+}
+
+// Filter returns a new set, of the same implementation as s, holding the
+// keys for which keep returns true
+func Filter[K comparable](s Set[K], keep func(key K) bool) Set[K] {
+	res := s.Clone()
+	var drop []K
+	for k := range res.All() {
+		if !keep(k) {
+			drop = append(drop, k)
+		}
+	}
+	res.Drop(drop...)
+	return res
+}
+
+// Map returns a new set holding fn applied to every key of s
+func Map[K, V comparable](s Set[K], fn func(key K) V) Set[V] {
+	res := InitSet[V](s.Len())
+	for k := range s.All() {
+		res.Set(fn(k))
+	}
+	return res
+}
+
+func isSubset[K comparable](a, b Set[K]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	for k := range a.All() {
+		if !b.HasKey(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// the helpers below implement the in-place operations on top of the
+// primitive methods so that every Set implementation shares one definition
+
+func unionWith[K comparable](dst, src Set[K]) {
+	dst.Set(src.ToSlice()...)
+}
+
+func intersectWith[K comparable](dst, src Set[K]) {
+	var drop []K
+	for k := range dst.All() {
+		if !src.HasKey(k) {
+			drop = append(drop, k)
+		}
+	}
+	dst.Drop(drop...)
+}
+
+func differenceWith[K comparable](dst, src Set[K]) {
+	dst.Drop(src.ToSlice()...)
+}
+
+func symmetricDifferenceWith[K comparable](dst, src Set[K]) {
+	for _, k := range src.ToSlice() {
+		if dst.HasKey(k) {
+			dst.Drop(k)
+		} else {
+			dst.Set(k)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func sorted[K int | string](s Set[K]) []K {
+	sl := s.ToSlice()
+	slices.Sort(sl)
+	return sl
+}
+
+func TestSet_Algebra(t *testing.T) {
+	a := Setify(1, 2, 3, 4)
+	b := Setify(3, 4, 5)
+	tests := []struct {
+		name string
+		got  Set[int]
+		want []int
+	}{
+		{name: "union", got: a.Union(b), want: []int{1, 2, 3, 4, 5}},
+		{name: "intersect", got: a.Intersect(b), want: []int{3, 4}},
+		{name: "intersect reversed", got: b.Intersect(a), want: []int{3, 4}},
+		{name: "difference", got: a.Difference(b), want: []int{1, 2}},
+		{name: "symmetric difference", got: a.SymmetricDifference(b), want: []int{1, 2, 5}},
+		{name: "union func", got: Union(a, b, Setify(9)), want: []int{1, 2, 3, 4, 5, 9}},
+		{name: "intersect func", got: Intersect(a, b, Setify(4)), want: []int{4}},
+		{name: "difference func", got: Difference(b, a), want: []int{5}},
+		{name: "symmetric difference func", got: SymmetricDifference(b, a), want: []int{1, 2, 5}},
+		{name: "union none", got: Union[int](), want: []int{}},
+		{name: "intersect none", got: Intersect[int](), want: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sorted(tt.got); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := sorted(a); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("copying operations modified the receiver: %v", got)
+	}
+}
+
+func TestSet_AlgebraInPlace(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(s, other Set[int])
+		want []int
+	}{
+		{name: "union", op: Set[int].UnionWith, want: []int{1, 2, 3, 4, 5}},
+		{name: "intersect", op: Set[int].IntersectWith, want: []int{3, 4}},
+		{name: "difference", op: Set[int].DifferenceWith, want: []int{1, 2}},
+		{name: "symmetric difference", op: Set[int].SymmetricDifferenceWith, want: []int{1, 2, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Setify(1, 2, 3, 4)
+			tt.op(s, Setify(3, 4, 5))
+			if got := sorted(s); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the operand may be the receiver itself
+	s := Setify(1, 2, 3)
+	s.SymmetricDifferenceWith(s)
+	if s.Len() != 0 {
+		t.Errorf("s ^ s should be empty, got %v", sorted(s))
+	}
+	s = Setify(1, 2, 3)
+	s.DifferenceWith(s)
+	if s.Len() != 0 {
+		t.Errorf("s - s should be empty, got %v", sorted(s))
+	}
+	s = Setify(1, 2, 3)
+	s.DifferenceWith(Setify(2, 3, 4, 5, 6))
+	if got := sorted(s); !slices.Equal(got, []int{1}) {
+		t.Errorf("got %v, want [1]", got)
+	}
+}
+
+func TestSet_Relations(t *testing.T) {
+	a := Setify(1, 2)
+	b := Setify(1, 2, 3)
+	if !a.IsSubset(b) || !IsSubset(a, b) {
+		t.Error("a should be a subset of b")
+	}
+	if b.IsSubset(a) {
+		t.Error("b should not be a subset of a")
+	}
+	if !b.IsSuperset(a) || !IsSuperset(b, a) {
+		t.Error("b should be a superset of a")
+	}
+	if Setify(1, 4).IsSubset(b) {
+		t.Error("{1, 4} should not be a subset of b")
+	}
+	if a.Equal(b) || Equal(a, b) {
+		t.Error("a and b should not be equal")
+	}
+	if !a.Equal(Setify(2, 1)) || !Equal(b, b.Clone()) {
+		t.Error("sets with the same keys should be equal")
+	}
+	if !InitSet[int](0).IsSubset(a) {
+		t.Error("the empty set is a subset of every set")
+	}
+}
+
+func TestSet_Clone(t *testing.T) {
+	s := Setify("a", "b")
+	c := s.Clone()
+	c.Set("c")
+	if s.HasKey("c") {
+		t.Error("clone shares storage with the original")
+	}
+	if !c.HasKey("a", "b", "c") {
+		t.Errorf("clone lost keys: %v", sorted(c))
+	}
+}
+
+func TestSet_Iteration(t *testing.T) {
+	s := Setify(1, 2, 3, 4, 5)
+	var got []int
+	for k := range s.All() {
+		got = append(got, k)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("All() = %v", got)
+	}
+
+	n := 0
+	for range s.All() {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("All() did not stop on break, n = %d", n)
+	}
+
+	n = 0
+	s.Each(func(int) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t.Errorf("Each() did not stop when fn returned false, n = %d", n)
+	}
+}
+
+func TestFilterAndMap(t *testing.T) {
+	s := Setify(1, 2, 3, 4, 5, 6)
+	even := Filter(s, func(k int) bool { return k%2 == 0 })
+	if got := sorted(even); !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("Filter() = %v", got)
+	}
+	if s.Len() != 6 {
+		t.Errorf("Filter() modified its input")
+	}
+
+	mod := Map(s, func(k int) string { return string(rune('a' + k%3)) })
+	if got := sorted(mod); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("Map() = %v", got)
+	}
+}
@@ -0,0 +1,266 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// ConcurrentSet is a Set that is safe for concurrent use by multiple goroutines.
+// Keys are spread over independently locked shards, so single key operations
+// are atomic while operations spanning several keys (HasKey with many keys,
+// Len, ToSlice, the set algebra) observe each shard at a slightly different
+// moment.
+type ConcurrentSet[K comparable] interface {
+	Set[K]
+	// SetIfAbsent adds key and reports whether it was absent before the call
+	SetIfAbsent(key K) bool
+	// DropIfPresent removes key and reports whether it was present before the call
+	DropIfPresent(key K) bool
+}
+
+type shard[K comparable] struct {
+	mu sync.RWMutex
+	m  HashSet[K]
+}
+
+type concurrentSet[K comparable] struct {
+	seed   maphash.Seed
+	shards []*shard[K]
+}
+
+// InitConcurrentSet creates a ConcurrentSet with room for length keys spread
+// over shards locks. shards is rounded up to a power of two; when it is not
+// positive a value derived from GOMAXPROCS is used.
+func InitConcurrentSet[K comparable](length, shards int) ConcurrentSet[K] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0) * 4
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+	return newConcurrentSet[K](maphash.MakeSeed(), n, length)
+}
+
+// ConcurrentSetify creates a ConcurrentSet holding keys
+func ConcurrentSetify[K comparable](keys ...K) ConcurrentSet[K] {
+	s := InitConcurrentSet[K](len(keys), 0)
+	s.Set(keys...)
+	return s
+}
+
+func newConcurrentSet[K comparable](seed maphash.Seed, shards, length int) *concurrentSet[K] {
+	s := &concurrentSet[K]{
+		seed:   seed,
+		shards: make([]*shard[K], shards),
+	}
+	for i := range s.shards {
+		s.shards[i] = &shard[K]{m: make(HashSet[K], length/shards)}
+	}
+	return s
+}
+
+func (s *concurrentSet[K]) shardOf(key K) *shard[K] {
+	return s.shards[maphash.Comparable(s.seed, key)&uint64(len(s.shards)-1)]
+}
+
+func (s *concurrentSet[K]) Set(keys ...K) {
+	for _, key := range keys {
+		sh := s.shardOf(key)
+		sh.mu.Lock()
+		sh.m[key] = struct{}{}
+		sh.mu.Unlock()
+	}
+}
+
+func (s *concurrentSet[K]) SetIfAbsent(key K) bool {
+	sh := s.shardOf(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, ok := sh.m[key]; ok {
+		return false
+	}
+	sh.m[key] = struct{}{}
+	return true
+}
+
+func (s *concurrentSet[K]) DropIfPresent(key K) bool {
+	sh := s.shardOf(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, ok := sh.m[key]; !ok {
+		return false
+	}
+	delete(sh.m, key)
+	return true
+}
+
+func (s *concurrentSet[K]) has(key K) bool {
+	sh := s.shardOf(key)
+	sh.mu.RLock()
+	_, ok := sh.m[key]
+	sh.mu.RUnlock()
+	return ok
+}
+
+func (s *concurrentSet[K]) HasKey(keys ...K) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if !s.has(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *concurrentSet[K]) HasAny(keys ...K) bool {
+	for _, key := range keys {
+		if s.has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *concurrentSet[K]) Drop(keys ...K) {
+	for _, key := range keys {
+		sh := s.shardOf(key)
+		sh.mu.Lock()
+		delete(sh.m, key)
+		sh.mu.Unlock()
+	}
+}
+
+func (s *concurrentSet[K]) Len() int {
+	n := 0
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		n += len(sh.m)
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+func (s *concurrentSet[K]) DropAll() Set[K] {
+	s.Clear()
+	return s
+}
+
+func (s *concurrentSet[K]) Clear() {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		clear(sh.m)
+		sh.mu.Unlock()
+	}
+}
+
+func (s *concurrentSet[K]) Reset(capacity int) {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.m = make(HashSet[K], capacity/len(s.shards))
+		sh.mu.Unlock()
+	}
+}
+
+func (s *concurrentSet[K]) ToSlice() []K {
+	sl := make([]K, 0, s.Len())
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		for k := range sh.m {
+			sl = append(sl, k)
+		}
+		sh.mu.RUnlock()
+	}
+	return sl
+}
+
+func (s *concurrentSet[K]) Clone() Set[K] {
+	c := newConcurrentSet[K](s.seed, len(s.shards), 0)
+	for i, sh := range s.shards {
+		sh.mu.RLock()
+		c.shards[i].m = sh.m.clone()
+		sh.mu.RUnlock()
+	}
+	return c
+}
+
+// All yields a snapshot of every shard in turn; the lock of a shard is not
+// held while its keys are yielded, so the loop body may modify the set.
+func (s *concurrentSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, sh := range s.shards {
+			sh.mu.RLock()
+			keys := sh.m.ToSlice()
+			sh.mu.RUnlock()
+			for _, k := range keys {
+				if !yield(k) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *concurrentSet[K]) Each(fn func(key K) bool) {
+	for k := range s.All() {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func (s *concurrentSet[K]) Union(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.UnionWith(other)
+	return c
+}
+
+func (s *concurrentSet[K]) Intersect(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.IntersectWith(other)
+	return c
+}
+
+func (s *concurrentSet[K]) Difference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.DifferenceWith(other)
+	return c
+}
+
+func (s *concurrentSet[K]) SymmetricDifference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.SymmetricDifferenceWith(other)
+	return c
+}
+
+func (s *concurrentSet[K]) UnionWith(other Set[K]) {
+	unionWith[K](s, other)
+}
+
+func (s *concurrentSet[K]) IntersectWith(other Set[K]) {
+	intersectWith[K](s, other)
+}
+
+func (s *concurrentSet[K]) DifferenceWith(other Set[K]) {
+	differenceWith[K](s, other)
+}
+
+func (s *concurrentSet[K]) SymmetricDifferenceWith(other Set[K]) {
+	symmetricDifferenceWith[K](s, other)
+}
+
+func (s *concurrentSet[K]) IsSubset(other Set[K]) bool {
+	return isSubset[K](s, other)
+}
+
+func (s *concurrentSet[K]) IsSuperset(other Set[K]) bool {
+	return isSubset(other, Set[K](s))
+}
+
+func (s *concurrentSet[K]) Equal(other Set[K]) bool {
+	return s.Len() == other.Len() && isSubset[K](s, other)
+}
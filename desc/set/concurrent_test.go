@@ -0,0 +1,137 @@
+package set
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	s := InitConcurrentSet[int](16, 3)
+	if got := len(s.(*concurrentSet[int]).shards); got != 4 {
+		t.Errorf("shards = %d, want 4", got)
+	}
+	if s.HasKey() {
+		t.Error("HasKey() without keys should be false")
+	}
+	s.Set(1, 2, 3, 4)
+	if !s.HasKey(1, 2) || s.HasKey(1, 5) {
+		t.Error("HasKey() returned a wrong result")
+	}
+	if !s.HasAny(5, 4) || s.HasAny(5, 6) {
+		t.Error("HasAny() returned a wrong result")
+	}
+	s.Drop(1)
+	if got := sorted[int](s); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", s.Len())
+	}
+	if d := s.Clone().DropAll(); d.Len() != 0 {
+		t.Errorf("DropAll() returned %d keys", d.Len())
+	}
+
+	if !s.SetIfAbsent(5) || s.SetIfAbsent(5) {
+		t.Error("SetIfAbsent() should only report the first insertion")
+	}
+	if !s.DropIfPresent(5) || s.DropIfPresent(5) {
+		t.Error("DropIfPresent() should only report the first removal")
+	}
+
+	c := s.Clone()
+	c.Set(9)
+	if s.HasKey(9) {
+		t.Error("clone shares storage with the original")
+	}
+	if _, ok := c.(ConcurrentSet[int]); !ok {
+		t.Error("clone of a concurrent set should be a concurrent set")
+	}
+
+	n := 0
+	s.Each(func(int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Each() did not stop, n = %d", n)
+	}
+	// the set may be modified while iterating
+	for k := range s.All() {
+		s.Drop(k)
+	}
+	if s.Len() != 0 {
+		t.Errorf("Len() = %d after dropping every key", s.Len())
+	}
+}
+
+func TestConcurrentSet_Algebra(t *testing.T) {
+	a := ConcurrentSetify(1, 2, 3, 4)
+	b := Setify(3, 4, 5)
+	if got := sorted(a.Union(b)); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := sorted(a.Intersect(b)); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Intersect() = %v", got)
+	}
+	if got := sorted(a.Difference(b)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if got := sorted(a.SymmetricDifference(b)); !slices.Equal(got, []int{1, 2, 5}) {
+		t.Errorf("SymmetricDifference() = %v", got)
+	}
+	if !a.IsSuperset(Setify(1, 2)) || a.IsSubset(b) || !a.Equal(Setify(4, 3, 2, 1)) {
+		t.Error("relations returned a wrong result")
+	}
+	if got := sorted(b.Union(a)); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("mixed Union() = %v", got)
+	}
+	a.IntersectWith(a)
+	if a.Len() != 4 {
+		t.Errorf("a & a should keep every key, got %v", sorted[int](a))
+	}
+}
+
+func TestConcurrentSet_Race(t *testing.T) {
+	const (
+		workers = 16
+		keys    = 1000
+	)
+	s := InitConcurrentSet[int](keys, 0)
+	var added, dropped atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < keys; i++ {
+				if s.SetIfAbsent(i) {
+					added.Add(1)
+				}
+				s.Set(i + keys)
+				_ = s.HasKey(i, i+keys)
+				_ = s.Len()
+				if w%4 == 0 {
+					_ = s.ToSlice()
+				}
+				if s.DropIfPresent(i + keys) {
+					dropped.Add(1)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if added.Load() != keys {
+		t.Errorf("SetIfAbsent() succeeded %d times, want %d", added.Load(), keys)
+	}
+	if dropped.Load() < 1 || dropped.Load() > keys*workers {
+		t.Errorf("DropIfPresent() succeeded %d times", dropped.Load())
+	}
+	for i := 0; i < keys; i++ {
+		if !s.HasKey(i) {
+			t.Fatalf("key %d is missing", i)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+// Every Set implementation is encoded as a JSON array, as a gob encoded
+// slice, or as a YAML sequence through the Marshaler interfaces of the
+// common YAML libraries. Keys implementing encoding.TextMarshaler are
+// written as strings. Unordered implementations sort their keys first so that
+// the output is deterministic; LinkedSet keeps its insertion order. Decoding
+// replaces the current content of the set.
+
+// sortKeys sorts keys by value when K is a number or string kind and by
+// their JSON encoding otherwise
+func sortKeys[K comparable](keys []K) error {
+	if len(keys) < 2 {
+		return nil
+	}
+	var compare func(a, b reflect.Value) int
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Int(), b.Int()) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Uint(), b.Uint()) }
+	case reflect.Float32, reflect.Float64:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.Float(), b.Float()) }
+	case reflect.String:
+		compare = func(a, b reflect.Value) int { return cmp.Compare(a.String(), b.String()) }
+	}
+	if compare != nil {
+		slices.SortFunc(keys, func(a, b K) int {
+			return compare(reflect.ValueOf(a), reflect.ValueOf(b))
+		})
+		return nil
+	}
+
+	encoded := make(map[K][]byte, len(keys))
+	for _, k := range keys {
+		b, err := json.Marshal(k)
+		if err != nil {
+			return err
+		}
+		encoded[k] = b
+	}
+	slices.SortFunc(keys, func(a, b K) int {
+		return bytes.Compare(encoded[a], encoded[b])
+	})
+	return nil
+}
+
+func sortedSlice[K comparable](s Set[K]) ([]K, error) {
+	keys := s.ToSlice()
+	return keys, sortKeys(keys)
+}
+
+func gobEncode[K comparable](keys []K) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(keys); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gobDecode[K comparable](data []byte) ([]K, error) {
+	var keys []K
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&keys)
+	return keys, err
+}
+
+func (s HashSet[K]) MarshalJSON() ([]byte, error) {
+	keys, err := sortedSlice[K](s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(keys)
+}
+
+func (s *HashSet[K]) UnmarshalJSON(data []byte) error {
+	var keys []K
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s HashSet[K]) GobEncode() ([]byte, error) {
+	keys, err := sortedSlice[K](s)
+	if err != nil {
+		return nil, err
+	}
+	return gobEncode(keys)
+}
+
+func (s *HashSet[K]) GobDecode(data []byte) error {
+	keys, err := gobDecode[K](data)
+	if err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s HashSet[K]) MarshalYAML() (any, error) {
+	return sortedSlice[K](s)
+}
+
+func (s *HashSet[K]) UnmarshalYAML(unmarshal func(any) error) error {
+	var keys []K
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *HashSet[K]) replace(keys []K) {
+	if *s == nil {
+		*s = make(HashSet[K], len(keys))
+	} else {
+		clear(*s)
+	}
+	s.Set(keys...)
+}
+
+func (s *concurrentSet[K]) MarshalJSON() ([]byte, error) {
+	keys, err := sortedSlice[K](s)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(keys)
+}
+
+func (s *concurrentSet[K]) UnmarshalJSON(data []byte) error {
+	var keys []K
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *concurrentSet[K]) GobEncode() ([]byte, error) {
+	keys, err := sortedSlice[K](s)
+	if err != nil {
+		return nil, err
+	}
+	return gobEncode(keys)
+}
+
+func (s *concurrentSet[K]) GobDecode(data []byte) error {
+	keys, err := gobDecode[K](data)
+	if err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *concurrentSet[K]) MarshalYAML() (any, error) {
+	return sortedSlice[K](s)
+}
+
+func (s *concurrentSet[K]) UnmarshalYAML(unmarshal func(any) error) error {
+	var keys []K
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+// replace swaps in freshly filled shards one at a time
+func (s *concurrentSet[K]) replace(keys []K) {
+	fresh := newConcurrentSet[K](s.seed, len(s.shards), len(keys))
+	fresh.Set(keys...)
+	for i, sh := range s.shards {
+		sh.mu.Lock()
+		sh.m = fresh.shards[i].m
+		sh.mu.Unlock()
+	}
+}
+
+func (s *sortedSet[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *sortedSet[K]) UnmarshalJSON(data []byte) error {
+	var keys []K
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	s.root = nil
+	s.Set(keys...)
+	return nil
+}
+
+func (s *sortedSet[K]) GobEncode() ([]byte, error) {
+	return gobEncode(s.ToSlice())
+}
+
+func (s *sortedSet[K]) GobDecode(data []byte) error {
+	keys, err := gobDecode[K](data)
+	if err != nil {
+		return err
+	}
+	s.root = nil
+	s.Set(keys...)
+	return nil
+}
+
+func (s *sortedSet[K]) MarshalYAML() (any, error) {
+	return s.ToSlice(), nil
+}
+
+func (s *sortedSet[K]) UnmarshalYAML(unmarshal func(any) error) error {
+	var keys []K
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	s.root = nil
+	s.Set(keys...)
+	return nil
+}
+
+func (s *linkedSet[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+func (s *linkedSet[K]) UnmarshalJSON(data []byte) error {
+	var keys []K
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *linkedSet[K]) GobEncode() ([]byte, error) {
+	return gobEncode(s.ToSlice())
+}
+
+func (s *linkedSet[K]) GobDecode(data []byte) error {
+	keys, err := gobDecode[K](data)
+	if err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *linkedSet[K]) MarshalYAML() (any, error) {
+	return s.ToSlice(), nil
+}
+
+func (s *linkedSet[K]) UnmarshalYAML(unmarshal func(any) error) error {
+	var keys []K
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+	s.replace(keys)
+	return nil
+}
+
+func (s *linkedSet[K]) replace(keys []K) {
+	s.init(len(keys))
+	s.Set(keys...)
+}
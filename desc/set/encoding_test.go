@@ -0,0 +1,236 @@
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// point is a key type encoded through encoding.TextMarshaler
+type point struct {
+	X, Y int
+}
+
+func (p point) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, "%d:%d", p.X, p.Y), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &p.X, &p.Y)
+	return err
+}
+
+func TestHashSet_JSON(t *testing.T) {
+	type config struct {
+		Tags HashSet[string] `json:"tags"`
+		IDs  HashSet[int]    `json:"ids"`
+	}
+	cfg := config{
+		Tags: Setify("b", "c", "a").(HashSet[string]),
+		IDs:  Setify(10, 9, 100).(HashSet[int]),
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"tags":["a","b","c"],"ids":[9,10,100]}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got config
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Tags.Equal(cfg.Tags) || !got.IDs.Equal(cfg.IDs) {
+		t.Errorf("round trip = %+v, want %+v", got, cfg)
+	}
+
+	// decoding replaces the content of a non-empty set
+	s := Setify("x").(HashSet[string])
+	if err = json.Unmarshal([]byte(`["y","y"]`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []string{"y"}) {
+		t.Errorf("Unmarshal() = %v", got)
+	}
+	if err = json.Unmarshal([]byte(`{"y":1}`), &s); err == nil {
+		t.Error("Unmarshal() of an object should fail")
+	}
+}
+
+func TestHashSet_TextMarshalerKeys(t *testing.T) {
+	s := Setify(point{2, 1}, point{1, 2}, point{1, 1})
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["1:1","1:2","2:1"]`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+	var got HashSet[point]
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("round trip = %v", got.ToSlice())
+	}
+}
+
+func TestHashSet_Gob(t *testing.T) {
+	s := Setify(3, 1, 2).(HashSet[int])
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		t.Fatal(err)
+	}
+	var got HashSet[int]
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(s) {
+		t.Errorf("round trip = %v", got.ToSlice())
+	}
+	if err := got.GobDecode([]byte("garbage")); err == nil {
+		t.Error("GobDecode() of garbage should fail")
+	}
+}
+
+func TestHashSet_YAML(t *testing.T) {
+	s := Setify("b", "a").(HashSet[string])
+	v, err := s.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.([]string); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("MarshalYAML() = %v", got)
+	}
+
+	var got HashSet[string]
+	err = got.UnmarshalYAML(func(out any) error {
+		*out.(*[]string) = []string{"c", "d"}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(Setify("c", "d")) {
+		t.Errorf("UnmarshalYAML() = %v", got.ToSlice())
+	}
+}
+
+func TestImplementations_Encoding(t *testing.T) {
+	impls := map[string]struct {
+		set  Set[int]
+		want string
+	}{
+		"hash":       {set: Setify(3, 1, 2), want: "[1,2,3]"},
+		"concurrent": {set: ConcurrentSetify(3, 1, 2), want: "[1,2,3]"},
+		"sorted":     {set: SortedSetify(3, 1, 2), want: "[1,2,3]"},
+		"linked":     {set: LinkedSetify(3, 1, 2), want: "[3,1,2]"},
+	}
+	for name, tt := range impls {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(tt.set)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", data, tt.want)
+			}
+
+			// decode into a fresh set of the same implementation
+			got := tt.set.Clone().DropAll()
+			got.Set(42)
+			target := any(got)
+			if hs, ok := got.(HashSet[int]); ok {
+				target = &hs
+			}
+			if err = json.Unmarshal(data, target); err != nil {
+				t.Fatal(err)
+			}
+			if hs, ok := target.(*HashSet[int]); ok {
+				got = *hs
+			}
+			if !got.Equal(tt.set) {
+				t.Errorf("JSON round trip = %v", got.ToSlice())
+			}
+			if err = json.Unmarshal([]byte(`"x"`), target); err == nil {
+				t.Error("Unmarshal() of a string should fail")
+			}
+
+			enc, ok := tt.set.(gob.GobEncoder)
+			if !ok {
+				t.Fatal("not a gob.GobEncoder")
+			}
+			data, err = enc.GobEncode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			fresh := tt.set.Clone().DropAll()
+			dec := any(fresh)
+			if hs, ok := fresh.(HashSet[int]); ok {
+				dec = &hs
+			}
+			if err = dec.(gob.GobDecoder).GobDecode(data); err != nil {
+				t.Fatal(err)
+			}
+			if hs, ok := dec.(*HashSet[int]); ok {
+				fresh = *hs
+			}
+			if !fresh.Equal(tt.set) {
+				t.Errorf("gob round trip = %v", fresh.ToSlice())
+			}
+			if err = dec.(gob.GobDecoder).GobDecode(nil); err == nil {
+				t.Error("GobDecode() of empty input should fail")
+			}
+
+			y, ok := tt.set.(interface{ MarshalYAML() (any, error) })
+			if !ok {
+				t.Fatal("no MarshalYAML method")
+			}
+			v, err := y.MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(strings.Fields(fmt.Sprint(v)), ","); got != tt.want {
+				t.Errorf("MarshalYAML() = %v, want %s", v, tt.want)
+			}
+			uy := dec.(interface {
+				UnmarshalYAML(func(any) error) error
+			})
+			err = uy.UnmarshalYAML(func(out any) error {
+				*out.(*[]int) = []int{7}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = uy.UnmarshalYAML(func(any) error { return fmt.Errorf("boom") }); err == nil {
+				t.Error("UnmarshalYAML() should return the error of unmarshal")
+			}
+		})
+	}
+}
+
+func TestSortKeys(t *testing.T) {
+	floats := []float64{2.5, -1, 10}
+	if err := sortKeys(floats); err != nil || !slices.Equal(floats, []float64{-1, 2.5, 10}) {
+		t.Errorf("sortKeys() = %v, %v", floats, err)
+	}
+	uints := []uint8{20, 3, 100}
+	if err := sortKeys(uints); err != nil || !slices.Equal(uints, []uint8{3, 20, 100}) {
+		t.Errorf("sortKeys() = %v, %v", uints, err)
+	}
+	// keys of mixed types are ordered by their JSON encoding
+	mixed := []any{"b", 2, "a", 1}
+	if err := sortKeys(mixed); err != nil || fmt.Sprint(mixed) != "[a b 1 2]" {
+		t.Errorf("sortKeys() = %v, %v", mixed, err)
+	}
+	bad := []any{1, make(chan int)}
+	if err := sortKeys(bad); err == nil {
+		t.Error("sortKeys() of unencodable keys should fail")
+	}
+}
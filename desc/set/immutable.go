@@ -0,0 +1,312 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+)
+
+// ImmutableSet is a persistent set: it is never modified after creation, and
+// every method that would change it returns a new set instead, leaving the
+// receiver untouched. Versions share most of their structure, so a
+// modification costs O(log n) rather than a full copy, and an ImmutableSet
+// can be shared between goroutines without locking.
+type ImmutableSet[K comparable] interface {
+	// Set returns a set that also holds keys
+	Set(keys ...K) ImmutableSet[K]
+	// Drop returns a set without keys
+	Drop(keys ...K) ImmutableSet[K]
+	// Clear returns an empty set
+	Clear() ImmutableSet[K]
+	HasKey(keys ...K) bool
+	HasAny(keys ...K) bool
+	Len() int
+	ToSlice() []K
+	All() iter.Seq[K]
+	// Mutable returns a mutable copy of the set
+	Mutable() Set[K]
+}
+
+// the set is a hash array mapped trie: each level consumes hamtBits of the
+// key hash, and a node only stores the children that are present, indexed by
+// the popcount of a bitmap
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+var hamtSeed = maphash.MakeSeed()
+
+type hamtEntry[K comparable] struct {
+	hash uint64
+	key  K
+	// child is set when the entry is a sub-trie rather than a key
+	child *hamtNode[K]
+}
+
+type hamtNode[K comparable] struct {
+	bitmap  uint32
+	entries []hamtEntry[K]
+	// collisions holds keys whose whole hash is equal, it is only used once
+	// every bit of the hash has been consumed
+	collisions []K
+}
+
+func (n *hamtNode[K]) index(hash uint64, shift uint) (uint32, int) {
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[K]) has(hash uint64, key K, shift uint) bool {
+	for shift < 64 {
+		bit, pos := n.index(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[pos]
+		if e.child == nil {
+			return e.key == key
+		}
+		n = e.child
+		shift += hamtBits
+	}
+	for _, k := range n.collisions {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// with returns a node that also holds key, or n itself when key is present
+func (n *hamtNode[K]) with(hash uint64, key K, shift uint) *hamtNode[K] {
+	if shift >= 64 {
+		for _, k := range n.collisions {
+			if k == key {
+				return n
+			}
+		}
+		return &hamtNode[K]{collisions: append(n.collisions[:len(n.collisions):len(n.collisions)], key)}
+	}
+	bit, pos := n.index(hash, shift)
+	if n.bitmap&bit == 0 {
+		c := &hamtNode[K]{bitmap: n.bitmap | bit, entries: make([]hamtEntry[K], len(n.entries)+1)}
+		copy(c.entries, n.entries[:pos])
+		c.entries[pos] = hamtEntry[K]{hash: hash, key: key}
+		copy(c.entries[pos+1:], n.entries[pos:])
+		return c
+	}
+	e := n.entries[pos]
+	var child *hamtNode[K]
+	switch {
+	case e.child != nil:
+		child = e.child.with(hash, key, shift+hamtBits)
+		if child == e.child {
+			return n
+		}
+	case e.key == key:
+		return n
+	default:
+		child = newHamtPair(e, hamtEntry[K]{hash: hash, key: key}, shift+hamtBits)
+	}
+	c := &hamtNode[K]{bitmap: n.bitmap, entries: append([]hamtEntry[K](nil), n.entries...)}
+	c.entries[pos] = hamtEntry[K]{hash: hash, child: child}
+	return c
+}
+
+func newHamtPair[K comparable](a, b hamtEntry[K], shift uint) *hamtNode[K] {
+	if shift >= 64 {
+		return &hamtNode[K]{collisions: []K{a.key, b.key}}
+	}
+	n := &hamtNode[K]{}
+	ba, pa := uint32(1)<<((a.hash>>shift)&hamtMask), (a.hash>>shift)&hamtMask
+	bb, pb := uint32(1)<<((b.hash>>shift)&hamtMask), (b.hash>>shift)&hamtMask
+	switch {
+	case pa == pb:
+		n.bitmap = ba
+		n.entries = []hamtEntry[K]{{hash: a.hash, child: newHamtPair(a, b, shift+hamtBits)}}
+	case pa < pb:
+		n.bitmap = ba | bb
+		n.entries = []hamtEntry[K]{a, b}
+	default:
+		n.bitmap = ba | bb
+		n.entries = []hamtEntry[K]{b, a}
+	}
+	return n
+}
+
+// without returns a node that does not hold key, n itself when key is
+// absent, or nil when the node becomes empty
+func (n *hamtNode[K]) without(hash uint64, key K, shift uint) *hamtNode[K] {
+	if shift >= 64 {
+		for i, k := range n.collisions {
+			if k == key {
+				if len(n.collisions) == 1 {
+					return nil
+				}
+				rest := make([]K, 0, len(n.collisions)-1)
+				rest = append(rest, n.collisions[:i]...)
+				return &hamtNode[K]{collisions: append(rest, n.collisions[i+1:]...)}
+			}
+		}
+		return n
+	}
+	bit, pos := n.index(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n
+	}
+	e := n.entries[pos]
+	if e.child == nil {
+		if e.key != key {
+			return n
+		}
+		if len(n.entries) == 1 {
+			return nil
+		}
+		c := &hamtNode[K]{bitmap: n.bitmap &^ bit, entries: make([]hamtEntry[K], 0, len(n.entries)-1)}
+		c.entries = append(c.entries, n.entries[:pos]...)
+		c.entries = append(c.entries, n.entries[pos+1:]...)
+		return c
+	}
+	child := e.child.without(hash, key, shift+hamtBits)
+	if child == e.child {
+		return n
+	}
+	c := &hamtNode[K]{bitmap: n.bitmap, entries: append([]hamtEntry[K](nil), n.entries...)}
+	switch {
+	case child == nil:
+		// a sub-trie always holds at least two keys, so this cannot empty c
+		c.bitmap &^= bit
+		c.entries = append(c.entries[:pos], c.entries[pos+1:]...)
+	case len(child.collisions) == 1:
+		c.entries[pos] = hamtEntry[K]{hash: e.hash, key: child.collisions[0]}
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// pull a lone key up so that the trie stays as shallow as possible
+		c.entries[pos] = child.entries[0]
+	default:
+		c.entries[pos] = hamtEntry[K]{hash: e.hash, child: child}
+	}
+	return c
+}
+
+func (n *hamtNode[K]) all(yield func(K) bool) bool {
+	for _, k := range n.collisions {
+		if !yield(k) {
+			return false
+		}
+	}
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.all(yield) {
+				return false
+			}
+		} else if !yield(e.key) {
+			return false
+		}
+	}
+	return true
+}
+
+type immutableSet[K comparable] struct {
+	root *hamtNode[K]
+	size int
+}
+
+// InitImmutableSet returns an empty ImmutableSet
+func InitImmutableSet[K comparable]() ImmutableSet[K] {
+	return &immutableSet[K]{root: &hamtNode[K]{}}
+}
+
+// ImmutableSetify returns an ImmutableSet holding keys
+func ImmutableSetify[K comparable](keys ...K) ImmutableSet[K] {
+	return InitImmutableSet[K]().Set(keys...)
+}
+
+// Freeze returns an ImmutableSet holding the keys of s
+func Freeze[K comparable](s Set[K]) ImmutableSet[K] {
+	return ImmutableSetify(s.ToSlice()...)
+}
+
+func (s *immutableSet[K]) Set(keys ...K) ImmutableSet[K] {
+	root, size := s.root, s.size
+	for _, key := range keys {
+		h := maphash.Comparable(hamtSeed, key)
+		if r := root.with(h, key, 0); r != root {
+			root = r
+			size++
+		}
+	}
+	if root == s.root {
+		return s
+	}
+	return &immutableSet[K]{root: root, size: size}
+}
+
+func (s *immutableSet[K]) Drop(keys ...K) ImmutableSet[K] {
+	root, size := s.root, s.size
+	for _, key := range keys {
+		h := maphash.Comparable(hamtSeed, key)
+		if r := root.without(h, key, 0); r != root {
+			root = r
+			size--
+			if root == nil {
+				root = &hamtNode[K]{}
+			}
+		}
+	}
+	if root == s.root {
+		return s
+	}
+	return &immutableSet[K]{root: root, size: size}
+}
+
+func (s *immutableSet[K]) Clear() ImmutableSet[K] {
+	return InitImmutableSet[K]()
+}
+
+func (s *immutableSet[K]) has(key K) bool {
+	return s.root.has(maphash.Comparable(hamtSeed, key), key, 0)
+}
+
+func (s *immutableSet[K]) HasKey(keys ...K) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if !s.has(key) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *immutableSet[K]) HasAny(keys ...K) bool {
+	for _, key := range keys {
+		if s.has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *immutableSet[K]) Len() int {
+	return s.size
+}
+
+func (s *immutableSet[K]) ToSlice() []K {
+	sl := make([]K, 0, s.size)
+	for k := range s.All() {
+		sl = append(sl, k)
+	}
+	return sl
+}
+
+func (s *immutableSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.root.all(yield)
+	}
+}
+
+func (s *immutableSet[K]) Mutable() Set[K] {
+	return Setify(s.ToSlice()...)
+}
@@ -0,0 +1,163 @@
+package set
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestImmutableSet(t *testing.T) {
+	empty := InitImmutableSet[string]()
+	a := empty.Set("a", "b")
+	b := a.Set("c")
+	c := b.Drop("a", "z")
+
+	tests := []struct {
+		name string
+		set  ImmutableSet[string]
+		want []string
+	}{
+		{name: "empty", set: empty, want: []string{}},
+		{name: "a", set: a, want: []string{"a", "b"}},
+		{name: "b", set: b, want: []string{"a", "b", "c"}},
+		{name: "c", set: c, want: []string{"b", "c"}},
+		{name: "cleared", set: c.Clear(), want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.set.ToSlice()
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ToSlice() = %v, want %v", got, tt.want)
+			}
+			if tt.set.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", tt.set.Len(), len(tt.want))
+			}
+		})
+	}
+
+	if a.Set("a") != a || a.Drop("z") != a {
+		t.Error("no-op modifications should return the receiver")
+	}
+	if !b.HasKey("a", "c") || b.HasKey("a", "z") || b.HasKey() {
+		t.Error("HasKey() returned a wrong result")
+	}
+	if !c.HasAny("z", "b") || c.HasAny("a") {
+		t.Error("HasAny() returned a wrong result")
+	}
+
+	m := b.Mutable()
+	m.Drop("a")
+	if !b.HasKey("a") || m.HasKey("a") {
+		t.Error("Mutable() should return an independent copy")
+	}
+	if f := Freeze(m); !f.HasKey("b", "c") || f.Len() != 2 {
+		t.Errorf("Freeze() = %v", f.ToSlice())
+	}
+
+	n := 0
+	for range b.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All() did not stop, n = %d", n)
+	}
+}
+
+func TestImmutableSet_Random(t *testing.T) {
+	s := InitImmutableSet[int]()
+	ref := InitSet[int](0)
+	var versions []ImmutableSet[int]
+	var snapshots [][]int
+	for i := 0; i < 20000; i++ {
+		k := rand.IntN(2000)
+		if rand.IntN(3) == 0 {
+			s = s.Drop(k)
+			ref.Drop(k)
+		} else {
+			s = s.Set(k)
+			ref.Set(k)
+		}
+		if i%1000 == 0 {
+			versions = append(versions, s)
+			snapshots = append(snapshots, sorted(ref))
+		}
+	}
+	if got := sorted(s.Mutable()); !slices.Equal(got, sorted(ref)) {
+		t.Fatalf("content diverged from the reference set")
+	}
+	for i, v := range versions {
+		if got := sorted(v.Mutable()); !slices.Equal(got, snapshots[i]) {
+			t.Fatalf("version %d was modified by later operations", i)
+		}
+	}
+	for _, k := range s.ToSlice() {
+		s = s.Drop(k)
+	}
+	if s.Len() != 0 || len(s.ToSlice()) != 0 {
+		t.Errorf("set should be empty, got %v", s.ToSlice())
+	}
+}
+
+// TestHamt_Collisions drives the trie with hand made hashes so that keys
+// share long prefixes or the whole hash
+func TestHamt_Collisions(t *testing.T) {
+	const same = 0x0123456789abcdef
+	entries := []struct {
+		hash uint64
+		key  int
+	}{
+		{hash: same, key: 1},
+		{hash: same, key: 2},
+		{hash: same, key: 3},
+		{hash: same ^ 1<<63, key: 4},
+		{hash: same ^ 1<<5, key: 5},
+	}
+	root := &hamtNode[int]{}
+	for _, e := range entries {
+		root = root.with(e.hash, e.key, 0)
+		if root.with(e.hash, e.key, 0) != root {
+			t.Fatalf("inserting %d twice should be a no-op", e.key)
+		}
+	}
+	for _, e := range entries {
+		if !root.has(e.hash, e.key, 0) {
+			t.Errorf("key %d is missing", e.key)
+		}
+	}
+	if root.has(same, 9, 0) || root.has(same^1<<40, 1, 0) {
+		t.Error("has() found a key that was never inserted")
+	}
+
+	var got []int
+	root.all(func(k int) bool {
+		got = append(got, k)
+		return true
+	})
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("all() = %v", got)
+	}
+
+	if root.without(same, 9, 0) != root {
+		t.Error("removing an absent colliding key should be a no-op")
+	}
+	for i, e := range entries {
+		root = root.without(e.hash, e.key, 0)
+		if root == nil {
+			if i != len(entries)-1 {
+				t.Fatalf("trie emptied after removing %d keys", i+1)
+			}
+			break
+		}
+		for _, rest := range entries[i+1:] {
+			if !root.has(rest.hash, rest.key, 0) {
+				t.Fatalf("key %d lost after removing %d", rest.key, e.key)
+			}
+		}
+	}
+	if root != nil {
+		t.Error("trie should be empty")
+	}
+}
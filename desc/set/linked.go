@@ -0,0 +1,201 @@
+package set
+
+import "iter"
+
+type linkedNode[K comparable] struct {
+	key        K
+	prev, next *linkedNode[K]
+}
+
+// linkedSet remembers the order in which keys were first added. Setting a
+// key that is already present keeps its original position.
+type linkedSet[K comparable] struct {
+	nodes map[K]*linkedNode[K]
+	// root is the sentinel of a circular list, root.next is the oldest key
+	root linkedNode[K]
+}
+
+// InitLinkedSet creates a Set that iterates keys in insertion order
+func InitLinkedSet[K comparable](length int) Set[K] {
+	return newLinkedSet[K](length)
+}
+
+// LinkedSetify creates an insertion ordered Set holding keys
+func LinkedSetify[K comparable](keys ...K) Set[K] {
+	s := InitLinkedSet[K](len(keys))
+	s.Set(keys...)
+	return s
+}
+
+func newLinkedSet[K comparable](length int) *linkedSet[K] {
+	s := &linkedSet[K]{}
+	s.init(length)
+	return s
+}
+
+func (s *linkedSet[K]) init(length int) {
+	s.nodes = make(map[K]*linkedNode[K], length)
+	s.root.next = &s.root
+	s.root.prev = &s.root
+}
+
+func (s *linkedSet[K]) Set(keys ...K) {
+	for _, key := range keys {
+		if _, ok := s.nodes[key]; ok {
+			continue
+		}
+		n := &linkedNode[K]{key: key, prev: s.root.prev, next: &s.root}
+		s.root.prev.next = n
+		s.root.prev = n
+		s.nodes[key] = n
+	}
+}
+
+func (s *linkedSet[K]) HasKey(keys ...K) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if _, ok := s.nodes[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *linkedSet[K]) HasAny(keys ...K) bool {
+	for _, key := range keys {
+		if _, ok := s.nodes[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *linkedSet[K]) Drop(keys ...K) {
+	for _, key := range keys {
+		n, ok := s.nodes[key]
+		if !ok {
+			continue
+		}
+		n.prev.next = n.next
+		n.next.prev = n.prev
+		// keep n.next so that an iterator positioned on n can move on
+		n.prev = nil
+		delete(s.nodes, key)
+	}
+}
+
+func (s *linkedSet[K]) Len() int {
+	return len(s.nodes)
+}
+
+func (s *linkedSet[K]) DropAll() Set[K] {
+	s.Clear()
+	return s
+}
+
+func (s *linkedSet[K]) Clear() {
+	for n := s.root.next; n != &s.root; n = n.next {
+		n.prev = nil
+	}
+	clear(s.nodes)
+	s.root.next = &s.root
+	s.root.prev = &s.root
+}
+
+func (s *linkedSet[K]) Reset(capacity int) {
+	s.Clear()
+	s.init(capacity)
+}
+
+func (s *linkedSet[K]) ToSlice() []K {
+	sl := make([]K, 0, s.Len())
+	for n := s.root.next; n != &s.root; n = n.next {
+		sl = append(sl, n.key)
+	}
+	return sl
+}
+
+func (s *linkedSet[K]) Clone() Set[K] {
+	c := newLinkedSet[K](s.Len())
+	c.Set(s.ToSlice()...)
+	return c
+}
+
+func (s *linkedSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for n := s.root.next; n != &s.root; n = n.next {
+			if n.prev == nil {
+				// n was dropped by the loop body, skip the keys dropped after it
+				for n.next != &s.root && n.next.prev == nil {
+					n = n.next
+				}
+				continue
+			}
+			if !yield(n.key) {
+				return
+			}
+		}
+	}
+}
+
+func (s *linkedSet[K]) Each(fn func(key K) bool) {
+	for k := range s.All() {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func (s *linkedSet[K]) Union(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.UnionWith(other)
+	return c
+}
+
+func (s *linkedSet[K]) Intersect(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.IntersectWith(other)
+	return c
+}
+
+func (s *linkedSet[K]) Difference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.DifferenceWith(other)
+	return c
+}
+
+func (s *linkedSet[K]) SymmetricDifference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.SymmetricDifferenceWith(other)
+	return c
+}
+
+func (s *linkedSet[K]) UnionWith(other Set[K]) {
+	unionWith[K](s, other)
+}
+
+func (s *linkedSet[K]) IntersectWith(other Set[K]) {
+	intersectWith[K](s, other)
+}
+
+func (s *linkedSet[K]) DifferenceWith(other Set[K]) {
+	differenceWith[K](s, other)
+}
+
+func (s *linkedSet[K]) SymmetricDifferenceWith(other Set[K]) {
+	symmetricDifferenceWith[K](s, other)
+}
+
+func (s *linkedSet[K]) IsSubset(other Set[K]) bool {
+	return isSubset[K](s, other)
+}
+
+func (s *linkedSet[K]) IsSuperset(other Set[K]) bool {
+	return isSubset(other, Set[K](s))
+}
+
+func (s *linkedSet[K]) Equal(other Set[K]) bool {
+	return s.Len() == other.Len() && isSubset[K](s, other)
+}
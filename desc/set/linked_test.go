@@ -0,0 +1,78 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinkedSet(t *testing.T) {
+	s := LinkedSetify("c", "a", "b", "a")
+	if got := s.ToSlice(); !slices.Equal(got, []string{"c", "a", "b"}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+	s.Drop("a")
+	s.Set("d", "a")
+	if got := slices.Collect(s.All()); !slices.Equal(got, []string{"c", "b", "d", "a"}) {
+		t.Errorf("All() = %v", got)
+	}
+	if !s.HasKey("a", "b") || s.HasKey("a", "z") || !s.HasAny("z", "d") || s.HasAny("z") || s.HasKey() {
+		t.Error("lookups returned a wrong result")
+	}
+	if s.Len() != 4 || s.Clone().DropAll().Len() != 0 {
+		t.Error("wrong length")
+	}
+
+	c := s.Clone()
+	c.Set("e")
+	if s.HasKey("e") {
+		t.Error("clone shares storage with the original")
+	}
+	if got := c.ToSlice(); !slices.Equal(got, []string{"c", "b", "d", "a", "e"}) {
+		t.Errorf("clone lost the order: %v", got)
+	}
+}
+
+func TestLinkedSet_DropWhileIterating(t *testing.T) {
+	s := LinkedSetify(1, 2, 3, 4, 5, 6)
+	var seen []int
+	for k := range s.All() {
+		seen = append(seen, k)
+		if k == 2 {
+			s.Drop(2, 3, 4)
+		}
+	}
+	if !slices.Equal(seen, []int{1, 2, 5, 6}) {
+		t.Errorf("iteration visited %v", seen)
+	}
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 5, 6}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+}
+
+func TestLinkedSet_Algebra(t *testing.T) {
+	a := LinkedSetify(4, 3, 2, 1)
+	b := Setify(3, 4, 5)
+	if got := a.Union(b).ToSlice(); !slices.Equal(got[:4], []int{4, 3, 2, 1}) || len(got) != 5 {
+		t.Errorf("Union() = %v", got)
+	}
+	if got := a.Intersect(b).ToSlice(); !slices.Equal(got, []int{4, 3}) {
+		t.Errorf("Intersect() = %v", got)
+	}
+	if got := a.Difference(b).ToSlice(); !slices.Equal(got, []int{2, 1}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if got := a.SymmetricDifference(b).ToSlice(); !slices.Equal(got, []int{2, 1, 5}) {
+		t.Errorf("SymmetricDifference() = %v", got)
+	}
+	if !a.IsSuperset(Setify(1, 4)) || a.IsSubset(b) || !a.Equal(Setify(1, 2, 3, 4)) {
+		t.Error("relations returned a wrong result")
+	}
+	n := 0
+	a.Each(func(int) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Each() did not stop, n = %d", n)
+	}
+}
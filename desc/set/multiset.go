@@ -0,0 +1,185 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// Entry is a key of a MultiSet with its multiplicity
+type Entry[K comparable] struct {
+	Key   K
+	Count int
+}
+
+// MultiSet is a set that counts how many times each key was added
+type MultiSet[K comparable] interface {
+	// Add adds n occurrences of key, n <= 0 is a no-op
+	Add(key K, n int)
+	// Remove removes up to n occurrences of key and returns how many were removed
+	Remove(key K, n int) int
+	// Count returns the multiplicity of key
+	Count(key K) int
+	// Len returns the total number of occurrences
+	Len() int
+	// Distinct returns the number of distinct keys
+	Distinct() int
+	// MostCommon returns the n keys with the highest counts, from the most to
+	// the least common; n <= 0 returns every key. Ties are broken by the key
+	// order used when encoding sets.
+	MostCommon(n int) []Entry[K]
+	// All returns an iterator over the keys and their counts
+	All() iter.Seq2[K, int]
+
+	// Union returns a multiset where every count is the maximum of both operands
+	Union(other MultiSet[K]) MultiSet[K]
+	// Intersect returns a multiset where every count is the minimum of both operands
+	Intersect(other MultiSet[K]) MultiSet[K]
+	// Sum returns a multiset where every count is the sum of both operands
+	Sum(other MultiSet[K]) MultiSet[K]
+
+	Clone() MultiSet[K]
+	Clear()
+	// ToSet returns a Set holding every key with a positive count
+	ToSet() Set[K]
+}
+
+type multiSet[K comparable] struct {
+	counts map[K]int
+	total  int
+}
+
+// InitMultiSet creates an empty MultiSet with room for length distinct keys
+func InitMultiSet[K comparable](length int) MultiSet[K] {
+	return &multiSet[K]{counts: make(map[K]int, length)}
+}
+
+// MultiSetify creates a MultiSet holding keys, repeated keys are counted
+func MultiSetify[K comparable](keys ...K) MultiSet[K] {
+	m := InitMultiSet[K](len(keys))
+	for _, k := range keys {
+		m.Add(k, 1)
+	}
+	return m
+}
+
+// MultiSetFromSet creates a MultiSet holding every key of s once
+func MultiSetFromSet[K comparable](s Set[K]) MultiSet[K] {
+	m := InitMultiSet[K](s.Len())
+	for k := range s.All() {
+		m.Add(k, 1)
+	}
+	return m
+}
+
+func (m *multiSet[K]) Add(key K, n int) {
+	if n <= 0 {
+		return
+	}
+	m.counts[key] += n
+	m.total += n
+}
+
+func (m *multiSet[K]) Remove(key K, n int) int {
+	c, ok := m.counts[key]
+	if !ok || n <= 0 {
+		return 0
+	}
+	if n >= c {
+		delete(m.counts, key)
+		n = c
+	} else {
+		m.counts[key] = c - n
+	}
+	m.total -= n
+	return n
+}
+
+func (m *multiSet[K]) Count(key K) int {
+	return m.counts[key]
+}
+
+func (m *multiSet[K]) Len() int {
+	return m.total
+}
+
+func (m *multiSet[K]) Distinct() int {
+	return len(m.counts)
+}
+
+func (m *multiSet[K]) MostCommon(n int) []Entry[K] {
+	keys := make([]K, 0, len(m.counts))
+	for k := range m.counts {
+		keys = append(keys, k)
+	}
+	// an encoding error only leaves ties in map order
+	_ = sortKeys(keys)
+	slices.SortStableFunc(keys, func(a, b K) int {
+		return cmp.Compare(m.counts[b], m.counts[a])
+	})
+	if n > 0 && n < len(keys) {
+		keys = keys[:n]
+	}
+	res := make([]Entry[K], len(keys))
+	for i, k := range keys {
+		res[i] = Entry[K]{Key: k, Count: m.counts[k]}
+	}
+	return res
+}
+
+func (m *multiSet[K]) All() iter.Seq2[K, int] {
+	return func(yield func(K, int) bool) {
+		for k, c := range m.counts {
+			if !yield(k, c) {
+				return
+			}
+		}
+	}
+}
+
+func (m *multiSet[K]) Union(other MultiSet[K]) MultiSet[K] {
+	res := m.Clone()
+	for k, c := range other.All() {
+		if d := c - res.Count(k); d > 0 {
+			res.Add(k, d)
+		}
+	}
+	return res
+}
+
+func (m *multiSet[K]) Intersect(other MultiSet[K]) MultiSet[K] {
+	res := InitMultiSet[K](0)
+	for k, c := range m.counts {
+		res.Add(k, min(c, other.Count(k)))
+	}
+	return res
+}
+
+func (m *multiSet[K]) Sum(other MultiSet[K]) MultiSet[K] {
+	res := m.Clone()
+	for k, c := range other.All() {
+		res.Add(k, c)
+	}
+	return res
+}
+
+func (m *multiSet[K]) Clone() MultiSet[K] {
+	c := &multiSet[K]{counts: make(map[K]int, len(m.counts)), total: m.total}
+	for k, n := range m.counts {
+		c.counts[k] = n
+	}
+	return c
+}
+
+func (m *multiSet[K]) Clear() {
+	clear(m.counts)
+	m.total = 0
+}
+
+func (m *multiSet[K]) ToSet() Set[K] {
+	s := InitSet[K](len(m.counts))
+	for k := range m.counts {
+		s.Set(k)
+	}
+	return s
+}
@@ -0,0 +1,126 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMultiSet(t *testing.T) {
+	m := MultiSetify("a", "b", "a", "c", "a", "b")
+	if m.Count("a") != 3 || m.Count("b") != 2 || m.Count("z") != 0 {
+		t.Errorf("unexpected counts a=%d b=%d z=%d", m.Count("a"), m.Count("b"), m.Count("z"))
+	}
+	if m.Len() != 6 || m.Distinct() != 3 {
+		t.Errorf("Len() = %d, Distinct() = %d", m.Len(), m.Distinct())
+	}
+
+	m.Add("c", 4)
+	m.Add("c", 0)
+	m.Add("c", -3)
+	if m.Count("c") != 5 || m.Len() != 10 {
+		t.Errorf("Add() count = %d, len = %d", m.Count("c"), m.Len())
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		n       int
+		removed int
+		left    int
+	}{
+		{name: "partial", key: "c", n: 2, removed: 2, left: 3},
+		{name: "too many", key: "b", n: 5, removed: 2, left: 0},
+		{name: "absent", key: "z", n: 1, removed: 0, left: 0},
+		{name: "non positive", key: "a", n: 0, removed: 0, left: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.Remove(tt.key, tt.n); got != tt.removed {
+				t.Errorf("Remove() = %d, want %d", got, tt.removed)
+			}
+			if got := m.Count(tt.key); got != tt.left {
+				t.Errorf("Count() = %d, want %d", got, tt.left)
+			}
+		})
+	}
+	if m.Len() != 6 || m.Distinct() != 2 {
+		t.Errorf("Len() = %d, Distinct() = %d", m.Len(), m.Distinct())
+	}
+
+	m.Clear()
+	if m.Len() != 0 || m.Distinct() != 0 {
+		t.Error("Clear() should empty the multiset")
+	}
+}
+
+func TestMultiSet_MostCommon(t *testing.T) {
+	m := MultiSetify(5, 1, 1, 2, 2, 3, 3, 3, 4)
+	want := []Entry[int]{{3, 3}, {1, 2}, {2, 2}, {4, 1}, {5, 1}}
+	if got := m.MostCommon(0); !slices.Equal(got, want) {
+		t.Errorf("MostCommon(0) = %v, want %v", got, want)
+	}
+	if got := m.MostCommon(2); !slices.Equal(got, want[:2]) {
+		t.Errorf("MostCommon(2) = %v, want %v", got, want[:2])
+	}
+	if got := m.MostCommon(10); len(got) != 5 {
+		t.Errorf("MostCommon(10) returned %d entries", len(got))
+	}
+}
+
+func TestMultiSet_Algebra(t *testing.T) {
+	a := MultiSetify("x", "x", "x", "y")
+	b := MultiSetify("x", "y", "y", "z")
+
+	tests := []struct {
+		name string
+		got  MultiSet[string]
+		want map[string]int
+	}{
+		{name: "union", got: a.Union(b), want: map[string]int{"x": 3, "y": 2, "z": 1}},
+		{name: "intersect", got: a.Intersect(b), want: map[string]int{"x": 1, "y": 1}},
+		{name: "sum", got: a.Sum(b), want: map[string]int{"x": 4, "y": 3, "z": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 0
+			for k, c := range tt.want {
+				total += c
+				if got := tt.got.Count(k); got != c {
+					t.Errorf("Count(%q) = %d, want %d", k, got, c)
+				}
+			}
+			if tt.got.Len() != total || tt.got.Distinct() != len(tt.want) {
+				t.Errorf("Len() = %d, Distinct() = %d", tt.got.Len(), tt.got.Distinct())
+			}
+		})
+	}
+	if a.Count("x") != 3 || a.Len() != 4 {
+		t.Error("operations modified the receiver")
+	}
+
+	c := a.Clone()
+	c.Add("y", 1)
+	if a.Count("y") != 1 {
+		t.Error("clone shares storage with the original")
+	}
+
+	n := 0
+	for range a.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("All() did not stop, n = %d", n)
+	}
+}
+
+func TestMultiSet_Conversion(t *testing.T) {
+	m := MultiSetify(1, 1, 2)
+	if s := m.ToSet(); !s.Equal(Setify(1, 2)) {
+		t.Errorf("ToSet() = %v", s.ToSlice())
+	}
+	f := MultiSetFromSet(Setify(3, 4))
+	if f.Count(3) != 1 || f.Count(4) != 1 || f.Len() != 2 {
+		t.Errorf("MultiSetFromSet() counts 3=%d 4=%d", f.Count(3), f.Count(4))
+	}
+}
@@ -1,25 +1,63 @@
 package set
 
+import "iter"
+
 type Set[K comparable] interface {
 	Set(keys ...K)
 	HasKey(keys ...K) bool
 	HasAny(keys ...K) bool
 	Drop(key ...K)
 	Len() int
+	// DropAll removes every key in place and returns the receiver
 	DropAll() Set[K]
 	ToSlice() []K
+
+	// Clear removes every key in place, keeping the allocated storage for reuse
+	Clear()
+	// Reset removes every key and releases the storage, preallocating room
+	// for capacity keys where the implementation supports it
+	Reset(capacity int)
+
+	// Clone returns a shallow copy backed by the same implementation
+	Clone() Set[K]
+	// All returns an iterator over the keys
+	All() iter.Seq[K]
+	// Each calls fn for every key until fn returns false
+	Each(fn func(key K) bool)
+
+	// Union, Intersect, Difference and SymmetricDifference return a new set
+	// and leave both operands untouched
+	Union(other Set[K]) Set[K]
+	Intersect(other Set[K]) Set[K]
+	Difference(other Set[K]) Set[K]
+	SymmetricDifference(other Set[K]) Set[K]
+
+	// The *With variants store the result in the receiver
+	UnionWith(other Set[K])
+	IntersectWith(other Set[K])
+	DifferenceWith(other Set[K])
+	SymmetricDifferenceWith(other Set[K])
+
+	IsSubset(other Set[K]) bool
+	IsSuperset(other Set[K]) bool
+	Equal(other Set[K]) bool
 }
 
-type set[K comparable] map[K]struct{}
+// HashSet is the map backed Set returned by InitSet and Setify. It is
+// exported so that it can be used directly as a field type, e.g. in a config
+// struct decoded from JSON. Like any map, a HashSet must be created with
+// make, InitSet or by decoding before keys are added: the zero value reads as
+// an empty set but Set on it panics.
+type HashSet[K comparable] map[K]struct{}
 
-func (s set[K]) Set(keys ...K) {
+func (s HashSet[K]) Set(keys ...K) {
 	for _, key := range keys {
 		s[key] = struct{}{}
 	}
 }
 
 // 是否包含全部key
-func (s set[K]) HasKey(keys ...K) bool {
+func (s HashSet[K]) HasKey(keys ...K) bool {
 	if len(keys) == 0 {
 		return false
 	}
@@ -32,22 +70,32 @@ func (s set[K]) HasKey(keys ...K) bool {
 	return true
 }
 
-func (s set[K]) Drop(keys ...K) {
+func (s HashSet[K]) Drop(keys ...K) {
 	for _, k := range keys {
 		delete(s, k)
 	}
 }
 
-func (s set[K]) Len() int {
+func (s HashSet[K]) Len() int {
 	return len(s)
 }
 
-func (s set[K]) DropAll() Set[K] {
-	return make(set[K])
+func (s HashSet[K]) DropAll() Set[K] {
+	clear(s)
+	return s
+}
+
+func (s HashSet[K]) Clear() {
+	clear(s)
+}
+
+// Reset is the same as Clear, a map value cannot replace its own storage
+func (s HashSet[K]) Reset(int) {
+	clear(s)
 }
 
 func InitSet[K comparable](length int) Set[K] {
-	return make(set[K], length)
+	return make(HashSet[K], length)
 }
 
 func Setify[K comparable](keys ...K) Set[K] {
@@ -56,7 +104,7 @@ func Setify[K comparable](keys ...K) Set[K] {
 	return s
 }
 
-func (s set[K]) ToSlice() []K {
+func (s HashSet[K]) ToSlice() []K {
 	sl := make([]K, 0, s.Len())
 	for k := range s {
 		sl = append(sl, k)
@@ -64,7 +112,7 @@ func (s set[K]) ToSlice() []K {
 	return sl
 }
 
-func (s set[K]) HasAny(keys ...K) bool {
+func (s HashSet[K]) HasAny(keys ...K) bool {
 	for _, key := range keys {
 		if s.HasKey(key) {
 			return true
@@ -72,3 +120,121 @@ func (s set[K]) HasAny(keys ...K) bool {
 	}
 	return false
 }
+
+func (s HashSet[K]) Clone() Set[K] {
+	return s.clone()
+}
+
+func (s HashSet[K]) clone() HashSet[K] {
+	c := make(HashSet[K], len(s))
+	for k := range s {
+		c[k] = struct{}{}
+	}
+	return c
+}
+
+func (s HashSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func (s HashSet[K]) Each(fn func(key K) bool) {
+	for k := range s {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func (s HashSet[K]) Union(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.UnionWith(other)
+	return c
+}
+
+func (s HashSet[K]) Intersect(other Set[K]) Set[K] {
+	small, large := Set[K](s), other
+	if other.Len() < s.Len() {
+		small, large = other, s
+	}
+	c := make(HashSet[K], small.Len())
+	for k := range small.All() {
+		if large.HasKey(k) {
+			c[k] = struct{}{}
+		}
+	}
+	return c
+}
+
+func (s HashSet[K]) Difference(other Set[K]) Set[K] {
+	c := make(HashSet[K], len(s))
+	for k := range s {
+		if !other.HasKey(k) {
+			c[k] = struct{}{}
+		}
+	}
+	return c
+}
+
+func (s HashSet[K]) SymmetricDifference(other Set[K]) Set[K] {
+	c := s.Difference(other)
+	for k := range other.All() {
+		if _, ok := s[k]; !ok {
+			c.Set(k)
+		}
+	}
+	return c
+}
+
+func (s HashSet[K]) UnionWith(other Set[K]) {
+	for k := range other.All() {
+		s[k] = struct{}{}
+	}
+}
+
+func (s HashSet[K]) IntersectWith(other Set[K]) {
+	for k := range s {
+		if !other.HasKey(k) {
+			delete(s, k)
+		}
+	}
+}
+
+func (s HashSet[K]) DifferenceWith(other Set[K]) {
+	if o, ok := other.(HashSet[K]); ok && len(o) > len(s) {
+		for k := range s {
+			if _, ok := o[k]; ok {
+				delete(s, k)
+			}
+		}
+		return
+	}
+	s.Drop(other.ToSlice()...)
+}
+
+func (s HashSet[K]) SymmetricDifferenceWith(other Set[K]) {
+	for _, k := range other.ToSlice() {
+		if _, ok := s[k]; ok {
+			delete(s, k)
+		} else {
+			s[k] = struct{}{}
+		}
+	}
+}
+
+func (s HashSet[K]) IsSubset(other Set[K]) bool {
+	return isSubset[K](s, other)
+}
+
+func (s HashSet[K]) IsSuperset(other Set[K]) bool {
+	return isSubset(other, Set[K](s))
+}
+
+func (s HashSet[K]) Equal(other Set[K]) bool {
+	return s.Len() == other.Len() && isSubset[K](s, other)
+}
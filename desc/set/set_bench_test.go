@@ -89,20 +89,107 @@ func BenchmarkSetify(b *testing.B) {
 	}
 }
 
-// BenchmarkSet_Concurrent benchmarks concurrent operations
-// Note: This benchmark is disabled due to concurrent map access issues
-// func BenchmarkSet_Concurrent(b *testing.B) {
-// 	s := InitSet[string](1000)
-//
-// 	b.ResetTimer()
-// 	b.RunParallel(func(pb *testing.PB) {
-// 		i := 0
-// 		for pb.Next() {
-// 			key := "key" + string(rune(i%1000))
-// 			s.Set(key)
-// 			_ = s.HasKey(key)
-// 			s.Drop(key)
-// 			i++
-// 		}
-// 	})
-// }
+// BenchmarkConcurrentSet benchmarks concurrent operations on a ConcurrentSet
+func BenchmarkConcurrentSet(b *testing.B) {
+	s := InitConcurrentSet[string](1000, 0)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := "key" + string(rune(i%1000))
+			s.Set(key)
+			_ = s.HasKey(key)
+			s.Drop(key)
+			i++
+		}
+	})
+}
+
+func benchmarkSets(n, overlap int) (Set[int], Set[int]) {
+	a, b := InitSet[int](n), InitSet[int](n)
+	for i := 0; i < n; i++ {
+		a.Set(i)
+		b.Set(i + n - overlap)
+	}
+	return a, b
+}
+
+// BenchmarkSet_Intersect benchmarks intersections of large sets
+func BenchmarkSet_Intersect(b *testing.B) {
+	s1, s2 := benchmarkSets(100000, 50000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s1.Intersect(s2)
+	}
+}
+
+// BenchmarkSet_IntersectUneven benchmarks intersecting a small set with a large one
+func BenchmarkSet_IntersectUneven(b *testing.B) {
+	large, _ := benchmarkSets(1000000, 0)
+	small, _ := benchmarkSets(1000, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = large.Intersect(small)
+	}
+}
+
+// BenchmarkSet_IntersectWith benchmarks in-place intersections of large sets
+func BenchmarkSet_IntersectWith(b *testing.B) {
+	s1, s2 := benchmarkSets(100000, 50000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		c := s1.Clone()
+		b.StartTimer()
+		c.IntersectWith(s2)
+	}
+}
+
+// BenchmarkIntersect benchmarks intersecting several large sets
+func BenchmarkIntersect(b *testing.B) {
+	s1, s2 := benchmarkSets(100000, 50000)
+	s3, _ := benchmarkSets(75000, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Intersect(s1, s2, s3)
+	}
+}
+
+// BenchmarkSet_Union benchmarks unions of large sets
+func BenchmarkSet_Union(b *testing.B) {
+	s1, s2 := benchmarkSets(100000, 50000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = s1.Union(s2)
+	}
+}
+
+// BenchmarkSortedSet_Set benchmarks insertion into a SortedSet
+func BenchmarkSortedSet_Set(b *testing.B) {
+	s := InitSortedSet[int]()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Set(i * 7919 % 100000)
+	}
+}
+
+// BenchmarkSortedSet_Range benchmarks range queries on a SortedSet
+func BenchmarkSortedSet_Range(b *testing.B) {
+	s := InitSortedSet[int]()
+	for i := 0; i < 100000; i++ {
+		s.Set(i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lo := i % 99000
+		_ = s.Range(lo, lo+100)
+	}
+}
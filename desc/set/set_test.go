@@ -25,3 +25,89 @@ func TestSet(t *testing.T) {
 	s.HasAny(1)
 	s.HasAny(4)
 }
+
+// TestImplementations checks that every implementation can be swapped in
+// behind the Set interface
+func TestImplementations(t *testing.T) {
+	impls := map[string]func() Set[int]{
+		"hash":       func() Set[int] { return InitSet[int](0) },
+		"concurrent": func() Set[int] { return InitConcurrentSet[int](0, 0) },
+		"sorted":     func() Set[int] { return InitSortedSet[int]() },
+		"linked":     func() Set[int] { return InitLinkedSet[int](0) },
+	}
+	for name, newSet := range impls {
+		t.Run(name, func(t *testing.T) {
+			s := newSet()
+			s.Set(5, 1, 3, 1)
+			s.Drop(3)
+			if s.Len() != 2 || !s.HasKey(1, 5) || s.HasAny(3) {
+				t.Errorf("unexpected content %v", s.ToSlice())
+			}
+			other := newSet()
+			other.Set(5, 7)
+			if !s.Union(other).Equal(Setify(1, 5, 7)) {
+				t.Errorf("Union() = %v", s.Union(other).ToSlice())
+			}
+			s.IntersectWith(other)
+			if !s.Equal(Setify(5)) {
+				t.Errorf("IntersectWith() = %v", s.ToSlice())
+			}
+		})
+	}
+}
+
+func TestImplementations_Lifecycle(t *testing.T) {
+	impls := map[string]func() Set[int]{
+		"hash":       func() Set[int] { return InitSet[int](0) },
+		"concurrent": func() Set[int] { return InitConcurrentSet[int](0, 0) },
+		"sorted":     func() Set[int] { return InitSortedSet[int]() },
+		"linked":     func() Set[int] { return InitLinkedSet[int](0) },
+	}
+	for name, newSet := range impls {
+		t.Run(name, func(t *testing.T) {
+			s := newSet()
+			s.Set(1, 2, 3)
+			d := s.DropAll()
+			d.Set(9)
+			if !s.HasKey(9) {
+				t.Error("DropAll() should return the receiver")
+			}
+			s.Drop(9)
+			if s.Len() != 0 || s.HasAny(1, 2, 3) {
+				t.Errorf("DropAll() left %v", s.ToSlice())
+			}
+
+			s.Set(4, 5)
+			s.Clear()
+			if s.Len() != 0 {
+				t.Errorf("Clear() left %v", s.ToSlice())
+			}
+			s.Set(6)
+
+			s.Reset(128)
+			if s.Len() != 0 {
+				t.Errorf("Reset() left %v", s.ToSlice())
+			}
+			s.Set(7)
+			if !s.Equal(Setify(7)) {
+				t.Errorf("set is unusable after Reset(): %v", s.ToSlice())
+			}
+		})
+	}
+}
+
+func TestHashSet_ZeroValue(t *testing.T) {
+	var cfg struct {
+		Tags HashSet[string]
+	}
+	// a HashSet field is a Set as it is, and reads as empty before make
+	var s Set[string] = cfg.Tags
+	if s.Len() != 0 || s.HasAny("a") || len(s.ToSlice()) != 0 {
+		t.Errorf("zero HashSet = %v", s.ToSlice())
+	}
+	cfg.Tags = make(HashSet[string])
+	cfg.Tags.Set("a")
+	if !cfg.Tags.Equal(Setify("a")) {
+		t.Errorf("HashSet after make = %v", cfg.Tags.ToSlice())
+	}
+}
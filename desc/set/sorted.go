@@ -0,0 +1,414 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+)
+
+// SortedSet is a Set whose keys are kept in ascending order. ToSlice, All and
+// Each visit keys from the smallest to the largest. Keys are ordered as by
+// cmp.Compare, so a NaN key sorts before every other float and is equal to
+// itself.
+type SortedSet[K cmp.Ordered] interface {
+	Set[K]
+	// Min returns the smallest key
+	Min() (K, bool)
+	// Max returns the largest key
+	Max() (K, bool)
+	// Floor returns the largest key less than or equal to key
+	Floor(key K) (K, bool)
+	// Ceiling returns the smallest key greater than or equal to key
+	Ceiling(key K) (K, bool)
+	// Range returns the keys in [lo, hi] in ascending order
+	Range(lo, hi K) []K
+	// Rank returns the number of keys less than key
+	Rank(key K) int
+	// Backward returns an iterator over the keys in descending order
+	Backward() iter.Seq[K]
+}
+
+// treapNode is a node of a treap: a binary search tree on key and a heap on
+// priority, which keeps the expected depth logarithmic. size counts the
+// nodes of the subtree and backs Rank.
+type treapNode[K cmp.Ordered] struct {
+	key         K
+	priority    uint32
+	size        int
+	left, right *treapNode[K]
+}
+
+func (n *treapNode[K]) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *treapNode[K]) update() {
+	n.size = 1 + n.left.sizeOf() + n.right.sizeOf()
+}
+
+// split divides n into the keys less than key and the keys greater than or
+// equal to key
+func split[K cmp.Ordered](n *treapNode[K], key K) (*treapNode[K], *treapNode[K]) {
+	if n == nil {
+		return nil, nil
+	}
+	if cmp.Less(n.key, key) {
+		l, r := split(n.right, key)
+		n.right = l
+		n.update()
+		return n, r
+	}
+	l, r := split(n.left, key)
+	n.left = r
+	n.update()
+	return l, n
+}
+
+// merge joins two treaps where every key of l is less than every key of r
+func merge[K cmp.Ordered](l, r *treapNode[K]) *treapNode[K] {
+	if l == nil {
+		return r
+	}
+	if r == nil {
+		return l
+	}
+	if l.priority > r.priority {
+		l.right = merge(l.right, r)
+		l.update()
+		return l
+	}
+	r.left = merge(l, r.left)
+	r.update()
+	return r
+}
+
+func cloneTreap[K cmp.Ordered](n *treapNode[K]) *treapNode[K] {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.left = cloneTreap(n.left)
+	c.right = cloneTreap(n.right)
+	return &c
+}
+
+type sortedSet[K cmp.Ordered] struct {
+	root *treapNode[K]
+}
+
+// InitSortedSet creates an empty SortedSet
+func InitSortedSet[K cmp.Ordered]() SortedSet[K] {
+	return &sortedSet[K]{}
+}
+
+// SortedSetify creates a SortedSet holding keys
+func SortedSetify[K cmp.Ordered](keys ...K) SortedSet[K] {
+	s := InitSortedSet[K]()
+	s.Set(keys...)
+	return s
+}
+
+func (s *sortedSet[K]) find(key K) *treapNode[K] {
+	n := s.root
+	for n != nil {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+func (s *sortedSet[K]) Set(keys ...K) {
+	for _, key := range keys {
+		if s.find(key) != nil {
+			continue
+		}
+		l, r := split(s.root, key)
+		n := &treapNode[K]{key: key, priority: rand.Uint32(), size: 1}
+		s.root = merge(merge(l, n), r)
+	}
+}
+
+func (s *sortedSet[K]) HasKey(keys ...K) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	for _, key := range keys {
+		if s.find(key) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sortedSet[K]) HasAny(keys ...K) bool {
+	for _, key := range keys {
+		if s.find(key) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sortedSet[K]) Drop(keys ...K) {
+	for _, key := range keys {
+		if s.find(key) == nil {
+			continue
+		}
+		l, r := split(s.root, key)
+		// r starts with key, cut it off
+		_, r = splitFirst(r)
+		s.root = merge(l, r)
+	}
+}
+
+// splitFirst detaches the smallest node of n
+func splitFirst[K cmp.Ordered](n *treapNode[K]) (*treapNode[K], *treapNode[K]) {
+	if n.left == nil {
+		rest := n.right
+		n.right = nil
+		n.update()
+		return n, rest
+	}
+	first, rest := splitFirst(n.left)
+	n.left = rest
+	n.update()
+	return first, n
+}
+
+func (s *sortedSet[K]) Len() int {
+	return s.root.sizeOf()
+}
+
+func (s *sortedSet[K]) DropAll() Set[K] {
+	s.Clear()
+	return s
+}
+
+func (s *sortedSet[K]) Clear() {
+	s.root = nil
+}
+
+// Reset is the same as Clear, a treap has no storage to preallocate
+func (s *sortedSet[K]) Reset(int) {
+	s.root = nil
+}
+
+func (s *sortedSet[K]) ToSlice() []K {
+	sl := make([]K, 0, s.Len())
+	for k := range s.All() {
+		sl = append(sl, k)
+	}
+	return sl
+}
+
+func (s *sortedSet[K]) Clone() Set[K] {
+	return &sortedSet[K]{root: cloneTreap(s.root)}
+}
+
+func (s *sortedSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		var stack []*treapNode[K]
+		n := s.root
+		for n != nil || len(stack) > 0 {
+			for n != nil {
+				stack = append(stack, n)
+				n = n.left
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key) {
+				return
+			}
+			n = n.right
+		}
+	}
+}
+
+func (s *sortedSet[K]) Backward() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		var stack []*treapNode[K]
+		n := s.root
+		for n != nil || len(stack) > 0 {
+			for n != nil {
+				stack = append(stack, n)
+				n = n.right
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key) {
+				return
+			}
+			n = n.left
+		}
+	}
+}
+
+func (s *sortedSet[K]) Each(fn func(key K) bool) {
+	for k := range s.All() {
+		if !fn(k) {
+			return
+		}
+	}
+}
+
+func (s *sortedSet[K]) Min() (K, bool) {
+	var zero K
+	n := s.root
+	if n == nil {
+		return zero, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.key, true
+}
+
+func (s *sortedSet[K]) Max() (K, bool) {
+	var zero K
+	n := s.root
+	if n == nil {
+		return zero, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, true
+}
+
+func (s *sortedSet[K]) Floor(key K) (K, bool) {
+	var (
+		res   K
+		found bool
+	)
+	n := s.root
+	for n != nil {
+		c := cmp.Compare(n.key, key)
+		if c > 0 {
+			n = n.left
+			continue
+		}
+		res, found = n.key, true
+		if c == 0 {
+			break
+		}
+		n = n.right
+	}
+	return res, found
+}
+
+func (s *sortedSet[K]) Ceiling(key K) (K, bool) {
+	var (
+		res   K
+		found bool
+	)
+	n := s.root
+	for n != nil {
+		c := cmp.Compare(n.key, key)
+		if c < 0 {
+			n = n.right
+			continue
+		}
+		res, found = n.key, true
+		if c == 0 {
+			break
+		}
+		n = n.left
+	}
+	return res, found
+}
+
+func (s *sortedSet[K]) Range(lo, hi K) []K {
+	var res []K
+	var walk func(n *treapNode[K])
+	walk = func(n *treapNode[K]) {
+		if n == nil {
+			return
+		}
+		if cmp.Less(lo, n.key) {
+			walk(n.left)
+		}
+		if !cmp.Less(n.key, lo) && !cmp.Less(hi, n.key) {
+			res = append(res, n.key)
+		}
+		if cmp.Less(n.key, hi) {
+			walk(n.right)
+		}
+	}
+	walk(s.root)
+	return res
+}
+
+func (s *sortedSet[K]) Rank(key K) int {
+	rank := 0
+	n := s.root
+	for n != nil {
+		if cmp.Less(n.key, key) {
+			rank += n.left.sizeOf() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return rank
+}
+
+func (s *sortedSet[K]) Union(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.UnionWith(other)
+	return c
+}
+
+func (s *sortedSet[K]) Intersect(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.IntersectWith(other)
+	return c
+}
+
+func (s *sortedSet[K]) Difference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.DifferenceWith(other)
+	return c
+}
+
+func (s *sortedSet[K]) SymmetricDifference(other Set[K]) Set[K] {
+	c := s.Clone()
+	c.SymmetricDifferenceWith(other)
+	return c
+}
+
+func (s *sortedSet[K]) UnionWith(other Set[K]) {
+	unionWith[K](s, other)
+}
+
+func (s *sortedSet[K]) IntersectWith(other Set[K]) {
+	intersectWith[K](s, other)
+}
+
+func (s *sortedSet[K]) DifferenceWith(other Set[K]) {
+	differenceWith[K](s, other)
+}
+
+func (s *sortedSet[K]) SymmetricDifferenceWith(other Set[K]) {
+	symmetricDifferenceWith[K](s, other)
+}
+
+func (s *sortedSet[K]) IsSubset(other Set[K]) bool {
+	return isSubset[K](s, other)
+}
+
+func (s *sortedSet[K]) IsSuperset(other Set[K]) bool {
+	return isSubset(other, Set[K](s))
+}
+
+func (s *sortedSet[K]) Equal(other Set[K]) bool {
+	return s.Len() == other.Len() && isSubset[K](s, other)
+}
@@ -0,0 +1,187 @@
+package set
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	s := SortedSetify(50, 10, 40, 20, 30, 10)
+	if got := s.ToSlice(); !slices.Equal(got, []int{10, 20, 30, 40, 50}) {
+		t.Errorf("ToSlice() = %v", got)
+	}
+	if got := slices.Collect(s.Backward()); !slices.Equal(got, []int{50, 40, 30, 20, 10}) {
+		t.Errorf("Backward() = %v", got)
+	}
+	if k, ok := s.Min(); !ok || k != 10 {
+		t.Errorf("Min() = %v, %v", k, ok)
+	}
+	if k, ok := s.Max(); !ok || k != 50 {
+		t.Errorf("Max() = %v, %v", k, ok)
+	}
+
+	tests := []struct {
+		name      string
+		key       int
+		floor     int
+		floorOK   bool
+		ceiling   int
+		ceilingOK bool
+		rank      int
+	}{
+		{name: "below min", key: 5, floorOK: false, ceiling: 10, ceilingOK: true, rank: 0},
+		{name: "on min", key: 10, floor: 10, floorOK: true, ceiling: 10, ceilingOK: true, rank: 0},
+		{name: "between", key: 25, floor: 20, floorOK: true, ceiling: 30, ceilingOK: true, rank: 2},
+		{name: "present", key: 30, floor: 30, floorOK: true, ceiling: 30, ceilingOK: true, rank: 2},
+		{name: "above max", key: 99, floor: 50, floorOK: true, ceilingOK: false, rank: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if k, ok := s.Floor(tt.key); ok != tt.floorOK || (ok && k != tt.floor) {
+				t.Errorf("Floor(%d) = %d, %v", tt.key, k, ok)
+			}
+			if k, ok := s.Ceiling(tt.key); ok != tt.ceilingOK || (ok && k != tt.ceiling) {
+				t.Errorf("Ceiling(%d) = %d, %v", tt.key, k, ok)
+			}
+			if r := s.Rank(tt.key); r != tt.rank {
+				t.Errorf("Rank(%d) = %d, want %d", tt.key, r, tt.rank)
+			}
+		})
+	}
+
+	if got := s.Range(15, 40); !slices.Equal(got, []int{20, 30, 40}) {
+		t.Errorf("Range(15, 40) = %v", got)
+	}
+	if got := s.Range(60, 70); len(got) != 0 {
+		t.Errorf("Range(60, 70) = %v", got)
+	}
+
+	s.Drop(30, 31)
+	if s.HasKey(30) || s.Len() != 4 || !s.HasAny(31, 40) || s.HasAny(31) {
+		t.Errorf("Drop() left %v", s.ToSlice())
+	}
+
+	empty := InitSortedSet[string]()
+	if _, ok := empty.Min(); ok {
+		t.Error("Min() of an empty set should fail")
+	}
+	if _, ok := empty.Max(); ok {
+		t.Error("Max() of an empty set should fail")
+	}
+	if empty.HasKey() {
+		t.Error("HasKey() without keys should be false")
+	}
+}
+
+func TestSortedSet_NaN(t *testing.T) {
+	nan := math.NaN()
+	// priorities are random, so repeat to cover different tree shapes
+	for range 100 {
+		s := SortedSetify(nan, 1.0, 2.0, nan, 3.0)
+		if s.Len() != 4 {
+			t.Fatalf("Len() = %d, want 4", s.Len())
+		}
+		if !s.HasKey(1.0, 2.0, 3.0, nan) {
+			t.Fatal("HasKey() = false for present keys")
+		}
+		got := s.ToSlice()
+		if !math.IsNaN(got[0]) || !slices.Equal(got[1:], []float64{1, 2, 3}) {
+			t.Fatalf("ToSlice() = %v, want [NaN 1 2 3]", got)
+		}
+		if k, ok := s.Min(); !ok || !math.IsNaN(k) {
+			t.Fatalf("Min() = %v, %v, want NaN", k, ok)
+		}
+		if k, ok := s.Floor(1.5); !ok || k != 1 {
+			t.Fatalf("Floor(1.5) = %v, %v", k, ok)
+		}
+		if k, ok := s.Floor(nan); !ok || !math.IsNaN(k) {
+			t.Fatalf("Floor(NaN) = %v, %v", k, ok)
+		}
+		if k, ok := s.Ceiling(nan); !ok || !math.IsNaN(k) {
+			t.Fatalf("Ceiling(NaN) = %v, %v", k, ok)
+		}
+		if r := s.Rank(1.0); r != 1 {
+			t.Fatalf("Rank(1) = %d, want 1", r)
+		}
+		if got := s.Range(nan, 2.0); len(got) != 3 || !math.IsNaN(got[0]) {
+			t.Fatalf("Range(NaN, 2) = %v", got)
+		}
+		s.Drop(nan)
+		if s.HasKey(nan) || s.Len() != 3 {
+			t.Fatal("Drop(NaN) did not remove NaN")
+		}
+	}
+}
+
+func TestSortedSet_Random(t *testing.T) {
+	s := InitSortedSet[int]()
+	ref := InitSet[int](0)
+	for i := 0; i < 5000; i++ {
+		k := rand.IntN(1000)
+		if rand.IntN(3) == 0 {
+			s.Drop(k)
+			ref.Drop(k)
+		} else {
+			s.Set(k)
+			ref.Set(k)
+		}
+	}
+	want := ref.ToSlice()
+	slices.Sort(want)
+	if got := s.ToSlice(); !slices.Equal(got, want) {
+		t.Fatalf("ToSlice() = %v, want %v", got, want)
+	}
+	for i, k := range want {
+		if r := s.Rank(k); r != i {
+			t.Fatalf("Rank(%d) = %d, want %d", k, r, i)
+		}
+	}
+}
+
+func TestSortedSet_Algebra(t *testing.T) {
+	a := SortedSetify(4, 3, 2, 1)
+	b := Setify(3, 4, 5)
+
+	u := a.Union(b)
+	if got := u.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Union() = %v", got)
+	}
+	if _, ok := u.(SortedSet[int]); !ok {
+		t.Error("Union() of a sorted set should be sorted")
+	}
+	if got := a.Intersect(b).ToSlice(); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Intersect() = %v", got)
+	}
+	if got := a.Difference(b).ToSlice(); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Difference() = %v", got)
+	}
+	if got := a.SymmetricDifference(b).ToSlice(); !slices.Equal(got, []int{1, 2, 5}) {
+		t.Errorf("SymmetricDifference() = %v", got)
+	}
+	if !a.IsSuperset(Setify(1, 4)) || a.IsSubset(b) || !a.Equal(Setify(1, 2, 3, 4)) {
+		t.Error("relations returned a wrong result")
+	}
+	if got := Filter[int](a, func(k int) bool { return k > 2 }).ToSlice(); !slices.Equal(got, []int{3, 4}) {
+		t.Errorf("Filter() = %v", got)
+	}
+	if a.Clone().DropAll().Len() != 0 {
+		t.Error("DropAll() should return an empty set")
+	}
+	n := 0
+	a.Each(func(int) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("Each() did not stop, n = %d", n)
+	}
+	for range a.Backward() {
+		n++
+		break
+	}
+	if n != 3 {
+		t.Errorf("Backward() did not stop, n = %d", n)
+	}
+}
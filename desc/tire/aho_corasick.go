@@ -0,0 +1,141 @@
+package tire
+
+// Match is an occurrence of a pattern found by a Matcher, text[Start:End]
+// is the matched text
+type Match struct {
+	Pattern    string
+	Start, End int
+}
+
+// Matcher finds every occurrence of a set of patterns in a single pass over
+// a text, using the Aho–Corasick automaton compiled from a Trie. The
+// automaton is a flat table of transitions, so matching costs one lookup
+// per byte of text plus one per match. A Matcher is never modified after
+// Compile and is safe for concurrent use.
+type Matcher struct {
+	cnt       int
+	charIndex func(byte) int
+	// next[s*cnt+i] is the state reached from s on the char of slot i, with
+	// the failure links already folded in
+	next []int32
+	// pattern[s] is the index in patterns of the word ending at s, or -1
+	pattern []int32
+	// output[s] is the nearest state on the failure chain of s, excluding s,
+	// where a word ends, or -1
+	output   []int32
+	patterns []string
+}
+
+// Compile builds a Matcher for the words of t. Later changes to t do not
+// affect the Matcher. Matching uses the charIndex of t, so an alphabet that
+// maps upper and lower case to the same slot, such as CaseFoldIndex, gives
+// case-insensitive matching; the reported Pattern is then the word as it was
+// first inserted. The empty word matches nothing.
+func (t *Trie) Compile() *Matcher {
+	m := &Matcher{cnt: t.cnt, charIndex: t.charIndex}
+	// number the nodes in breadth first order, so that the failure state of
+	// a node is always numbered before it
+	nodes := []*trieNode{t.root}
+	for i := 0; i < len(nodes); i++ {
+		for _, c := range nodes[i].children {
+			if c != nil {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	ids := make(map[*trieNode]int32, len(nodes))
+	for i, n := range nodes {
+		ids[n] = int32(i)
+	}
+
+	m.next = make([]int32, len(nodes)*t.cnt)
+	m.pattern = make([]int32, len(nodes))
+	m.output = make([]int32, len(nodes))
+	fail := make([]int32, len(nodes))
+	for s, n := range nodes {
+		m.pattern[s], m.output[s] = -1, -1
+		if n.isWordEnd && s > 0 {
+			m.pattern[s] = int32(len(m.patterns))
+			m.patterns = append(m.patterns, n.word)
+		}
+		if s > 0 {
+			f := fail[s]
+			if m.pattern[f] >= 0 {
+				m.output[s] = f
+			} else {
+				m.output[s] = m.output[f]
+			}
+		}
+		for i, c := range n.children {
+			row := s * t.cnt
+			switch {
+			case c == nil && s == 0:
+				m.next[row+i] = 0
+			case c == nil:
+				m.next[row+i] = m.next[int(fail[s])*t.cnt+i]
+			default:
+				id := ids[c]
+				m.next[row+i] = id
+				// the failure state of a child of the root is the root
+				if s > 0 {
+					fail[id] = m.next[int(fail[s])*t.cnt+i]
+				}
+			}
+		}
+	}
+	return m
+}
+
+// FindAll returns every occurrence of every pattern in text, overlapping
+// ones included, ordered by End and then by decreasing length
+func (m *Matcher) FindAll(text string) []Match {
+	var res []Match
+	m.scan(text, func(pattern string, end int) bool {
+		res = append(res, Match{Pattern: pattern, Start: end - len(pattern), End: end})
+		return true
+	})
+	return res
+}
+
+// Contains reports whether text holds any of the patterns
+func (m *Matcher) Contains(text string) bool {
+	found := false
+	m.scan(text, func(string, int) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// scan runs the automaton over text and calls fn for every match until fn
+// returns false. Bytes outside the alphabet cannot be part of a pattern, so
+// they send the automaton back to the root.
+func (m *Matcher) scan(text string, fn func(pattern string, end int) bool) {
+	s := int32(0)
+	for i := 0; i < len(text); i++ {
+		index := m.charIndex(text[i])
+		if index == -1 {
+			s = 0
+			continue
+		}
+		s = m.next[int(s)*m.cnt+index]
+		for o := s; o >= 0; o = m.output[o] {
+			if p := m.pattern[o]; p >= 0 && !fn(m.patterns[p], i+1) {
+				return
+			}
+		}
+	}
+}
+
+// CaseFoldIndex is a charIndex for InitTrie(26, 0, CaseFoldIndex) that maps
+// upper and lower case ASCII letters to the same 26 slots, for
+// case-insensitive tries and matchers
+func CaseFoldIndex(c byte) int {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a')
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A')
+	}
+	return -1
+}
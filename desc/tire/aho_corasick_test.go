@@ -0,0 +1,146 @@
+package tire
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func compile(t *testing.T, cnt int, headChar byte, charIndex func(byte) int, patterns ...string) *Matcher {
+	t.Helper()
+	trie := InitTrie(cnt, headChar, charIndex)
+	for _, p := range patterns {
+		if err := trie.Insert(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return trie.Compile()
+}
+
+func TestMatcher_FindAll(t1 *testing.T) {
+	m := compile(t1, 26, 'a', nil, "he", "she", "his", "hers", "")
+	tests := []struct {
+		text string
+		want []Match
+	}{
+		{text: "ushers", want: []Match{{"she", 1, 4}, {"he", 2, 4}, {"hers", 2, 6}}},
+		{text: "ahishers", want: []Match{{"his", 1, 4}, {"she", 3, 6}, {"he", 4, 6}, {"hers", 4, 8}}},
+		// bytes outside the alphabet break a match
+		{text: "h e, she!", want: []Match{{"she", 5, 8}, {"he", 6, 8}}},
+		{text: "xyz", want: nil},
+		{text: "", want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.text, func(t1 *testing.T) {
+			if got := m.FindAll(tt.text); !slices.Equal(got, tt.want) {
+				t1.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+			if got := m.Contains(tt.text); got != (tt.want != nil) {
+				t1.Errorf("Contains() = %v", got)
+			}
+		})
+	}
+}
+
+func TestMatcher_Overlapping(t1 *testing.T) {
+	m := compile(t1, 26, 'a', nil, "a", "aa", "aaa")
+	want := []Match{
+		{"a", 0, 1},
+		{"aa", 0, 2}, {"a", 1, 2},
+		{"aaa", 0, 3}, {"aa", 1, 3}, {"a", 2, 3},
+	}
+	if got := m.FindAll("aaa"); !slices.Equal(got, want) {
+		t1.Errorf("FindAll() = %v, want %v", got, want)
+	}
+}
+
+func TestMatcher_CaseFold(t1 *testing.T) {
+	m := compile(t1, 26, 0, CaseFoldIndex, "Spam", "SCAM", "eggs")
+	want := []Match{{"Spam", 4, 8}, {"eggs", 13, 17}, {"SCAM", 19, 23}}
+	if got := m.FindAll("no: SPAM and EgGs, scam"); !slices.Equal(got, want) {
+		t1.Errorf("FindAll() = %v, want %v", got, want)
+	}
+}
+
+// TestMatcher_Random compares FindAll with a naive search for every pattern
+func TestMatcher_Random(t1 *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	word := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = 'a' + byte(r.IntN(3))
+		}
+		return string(b)
+	}
+	patterns := map[string]bool{}
+	for len(patterns) < 30 {
+		patterns[word(1+r.IntN(5))] = true
+	}
+	trie := InitTrie(3, 'a', nil)
+	for p := range patterns {
+		_ = trie.Insert(p)
+	}
+	m := trie.Compile()
+	// the matcher must not see words inserted after Compile
+	_ = trie.Insert("cccccccc")
+
+	text := word(2000)
+	var want []Match
+	for end := 1; end <= len(text); end++ {
+		for start := 0; start < end; start++ {
+			if patterns[text[start:end]] {
+				want = append(want, Match{text[start:end], start, end})
+			}
+		}
+	}
+	if got := m.FindAll(text); !slices.Equal(got, want) {
+		t1.Errorf("FindAll() found %d matches, want %d", len(got), len(want))
+	}
+}
+
+// benchKeywords returns n keywords and a text of about 64KB holding a few
+// of them
+func benchKeywords(n int) ([]string, string) {
+	r := rand.New(rand.NewPCG(11, 12))
+	keywords := benchWords(n)
+	var sb strings.Builder
+	for sb.Len() < 1<<16 {
+		if r.IntN(100) == 0 {
+			sb.WriteString(keywords[r.IntN(n)])
+		} else {
+			// filler words of 1 to 8 random letters
+			for range 1 + r.IntN(8) {
+				sb.WriteByte('a' + byte(r.IntN(26)))
+			}
+		}
+		sb.WriteByte(' ')
+	}
+	return keywords, sb.String()
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	for _, n := range []int{10, 1000} {
+		keywords, text := benchKeywords(n)
+		trie := InitTrie(26, 'a', nil)
+		for _, k := range keywords {
+			_ = trie.Insert(k)
+		}
+		m := trie.Compile()
+		b.Run(fmt.Sprintf("AhoCorasick/%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				m.FindAll(text)
+			}
+		})
+		b.Run(fmt.Sprintf("StringsContains/%d", n), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				for _, k := range keywords {
+					strings.Contains(text, k)
+				}
+			}
+		})
+	}
+}
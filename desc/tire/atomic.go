@@ -0,0 +1,99 @@
+package tire
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Clone returns a deep copy of t that shares nothing with it
+func (t *Trie) Clone() *Trie {
+	c := *t
+	c.root = t.root.clone()
+	return &c
+}
+
+func (n *trieNode) clone() *trieNode {
+	c := *n
+	c.children = make([]*trieNode, len(n.children))
+	for i, child := range n.children {
+		if child != nil {
+			c.children[i] = child.clone()
+		}
+	}
+	return &c
+}
+
+// AtomicTrie holds a Trie that many goroutines read while it is
+// occasionally replaced, for example when a keyword list is reloaded.
+// Readers never lock: they work on the current version, which is never
+// modified again once published, while writers build a new version and swap
+// it in atomically.
+type AtomicTrie struct {
+	current atomic.Pointer[Trie]
+	// mu serializes writers so that concurrent Updates do not lose changes
+	mu sync.Mutex
+}
+
+// NewAtomicTrie returns an AtomicTrie publishing t, which must not be
+// modified afterwards
+func NewAtomicTrie(t *Trie) *AtomicTrie {
+	a := &AtomicTrie{}
+	a.current.Store(t)
+	return a
+}
+
+// Load returns the current version. It must only be read, use Update to
+// change it.
+func (a *AtomicTrie) Load() *Trie {
+	return a.current.Load()
+}
+
+// Store publishes t, which must not be modified afterwards, replacing the
+// current version
+func (a *AtomicTrie) Store(t *Trie) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.current.Store(t)
+}
+
+// Update applies fn to a copy of the current version and publishes the copy
+// if fn succeeds. Readers keep seeing the previous version until then. The
+// copy costs O(size of the trie), so batch changes into one Update.
+func (a *AtomicTrie) Update(fn func(t *Trie) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	next := a.current.Load().Clone()
+	if err := fn(next); err != nil {
+		return err
+	}
+	a.current.Store(next)
+	return nil
+}
+
+// Contains reports whether word is in the current version
+func (a *AtomicTrie) Contains(word string) bool {
+	return a.Load().Contains(word)
+}
+
+// HasPrefix reports whether some word of the current version starts with
+// prefix
+func (a *AtomicTrie) HasPrefix(prefix string) bool {
+	return a.Load().HasPrefix(prefix)
+}
+
+// WordsWithPrefix returns the words of the current version starting with
+// prefix
+func (a *AtomicTrie) WordsWithPrefix(prefix string) []string {
+	return a.Load().WordsWithPrefix(prefix)
+}
+
+// TopK returns the k heaviest words of the current version starting with
+// prefix
+func (a *AtomicTrie) TopK(prefix string, k int) []Suggestion {
+	return a.Load().TopK(prefix, k)
+}
+
+// Len returns the number of words in the current version
+func (a *AtomicTrie) Len() int {
+	return a.Load().Len()
+}
@@ -0,0 +1,178 @@
+package tire
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/errgo.v2/errors"
+)
+
+func TestTrie_Clone(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	_ = t.InsertWeighted("apple", 3)
+	_ = t.Insert("app")
+	c := t.Clone()
+	_ = c.Insert("banana")
+	c.Delete("apple")
+	_ = c.InsertWeighted("app", 9)
+	if !t.Contains("apple") || t.Contains("banana") || t.Len() != 2 {
+		t1.Errorf("changing the clone changed the original: %v", t.WordsWithPrefix(""))
+	}
+	if w, _ := t.Weight("app"); w != 0 {
+		t1.Errorf("weight of app in the original = %d", w)
+	}
+	if got := c.WordsWithPrefix(""); !slices.Equal(got, []string{"app", "banana"}) || c.Len() != 2 {
+		t1.Errorf("clone holds %v", got)
+	}
+}
+
+func TestTrie_CloneReleasesOriginal(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	_ = t.Insert("apple")
+	released := make(chan struct{})
+	runtime.AddCleanup(t, func(ch chan struct{}) { close(ch) }, released)
+	c := t.Clone()
+	m := t.Compile()
+	t = nil
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		select {
+		case <-released:
+			if !c.Contains("apple") || !m.Contains("apples") {
+				t1.Error("clone or matcher lost its words")
+			}
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t1.Error("the clone or the matcher keeps the original trie alive")
+}
+
+func TestAtomicTrie(t1 *testing.T) {
+	a := NewAtomicTrie(InitTrie(26, 'a', nil))
+	if err := a.Update(func(t *Trie) error {
+		_ = t.InsertWeighted("go", 2)
+		return t.InsertWeighted("gopher", 5)
+	}); err != nil {
+		t1.Fatal(err)
+	}
+	old := a.Load()
+	errBad := errors.New("bad batch")
+	if err := a.Update(func(t *Trie) error {
+		_ = t.Insert("rust")
+		return errBad
+	}); err != errBad {
+		t1.Errorf("Update() error = %v", err)
+	}
+	if a.Load() != old || a.Contains("rust") {
+		t1.Error("a failed Update() was published")
+	}
+	if !a.Contains("go") || !a.HasPrefix("goph") || a.Len() != 2 {
+		t1.Error("the current version lost words")
+	}
+	if got := a.WordsWithPrefix("go"); !slices.Equal(got, []string{"go", "gopher"}) {
+		t1.Errorf("WordsWithPrefix() = %v", got)
+	}
+	if got := a.TopK("", 1); !slices.Equal(got, []Suggestion{{"gopher", 5}}) {
+		t1.Errorf("TopK() = %v", got)
+	}
+
+	next := InitTrie(26, 'a', nil)
+	_ = next.Insert("zig")
+	a.Store(next)
+	if a.Contains("go") || !a.Contains("zig") {
+		t1.Error("Store() did not replace the version")
+	}
+}
+
+// TestAtomicTrie_Reload runs readers while writers reload and update the
+// trie. Every version holds the words w0..wN of one generation, so a reader
+// must never see words of two generations at once.
+func TestAtomicTrie_Reload(t1 *testing.T) {
+	const words = 50
+	build := func(gen int) *Trie {
+		t := InitTrie(36, 0, func(c byte) int {
+			switch {
+			case c >= '0' && c <= '9':
+				return int(c - '0')
+			case c >= 'a' && c <= 'z':
+				return int(c-'a') + 10
+			}
+			return -1
+		})
+		for i := 0; i < words; i++ {
+			_ = t.Insert(fmt.Sprintf("g%dw%d", gen, i))
+		}
+		return t
+	}
+	a := NewAtomicTrie(build(0))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				t := a.Load()
+				all := t.WordsWithPrefix("")
+				if t.Len() != len(all) {
+					t1.Errorf("reader saw %d words, Len() = %d", len(all), t.Len())
+					return
+				}
+				gens := t.WordsWithPrefix("g")
+				gen := gens[0][:strings.Index(gens[0], "w")+1]
+				if len(gens) != words || len(t.WordsWithPrefix(gen)) != words {
+					t1.Errorf("reader saw a torn version %v", all)
+					return
+				}
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for w := 0; w < 2; w++ {
+		writers.Add(1)
+		go func() {
+			defer writers.Done()
+			for gen := 1; gen <= 50; gen++ {
+				if gen%2 == w {
+					a.Store(build(gen))
+					continue
+				}
+				_ = a.Update(func(t *Trie) error {
+					return t.Insert(fmt.Sprintf("extra%d", gen))
+				})
+			}
+		}()
+	}
+	writers.Wait()
+	close(stop)
+	wg.Wait()
+}
+
+func BenchmarkAtomicTrie_Contains(b *testing.B) {
+	t := InitTrie(26, 'a', nil)
+	words := benchWords(10000)
+	for _, w := range words {
+		_ = t.Insert(w)
+	}
+	a := NewAtomicTrie(t)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			a.Contains(words[i%len(words)])
+			i++
+		}
+	})
+}
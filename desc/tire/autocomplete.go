@@ -0,0 +1,144 @@
+package tire
+
+import (
+	"container/heap"
+)
+
+// Suggestion is a word returned by TopK with its weight
+type Suggestion struct {
+	Word   string
+	Weight int
+}
+
+// FuzzyMatch is a word returned by Fuzzy with its edit distance to the query
+type FuzzyMatch struct {
+	Word     string
+	Distance int
+}
+
+// Weight returns the weight of word
+func (t *Trie) Weight(word string) (int, bool) {
+	n := t.node(word)
+	if n == nil || !n.isWordEnd {
+		return 0, false
+	}
+	return n.weight, true
+}
+
+// candidate is either a word or a subtree waiting to be expanded, ranked by
+// the weight of the word or the highest weight in the subtree. path is the
+// chars leading to the node, which orders ties; word the word as inserted.
+type candidate struct {
+	node   *trieNode
+	path   string
+	word   string
+	isWord bool
+	weight int
+}
+
+type candidates []candidate
+
+func (c candidates) Len() int { return len(c) }
+
+// Less puts the heaviest candidate first. Ties go to the smaller path and
+// then to a word over the subtree it heads, so that equally weighted words
+// come out in byte order.
+func (c candidates) Less(i, j int) bool {
+	switch {
+	case c[i].weight != c[j].weight:
+		return c[i].weight > c[j].weight
+	case c[i].path != c[j].path:
+		return c[i].path < c[j].path
+	}
+	return c[i].isWord && !c[j].isWord
+}
+
+func (c candidates) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+func (c *candidates) Push(x any) { *c = append(*c, x.(candidate)) }
+
+func (c *candidates) Pop() any {
+	old := *c
+	x := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return x
+}
+
+// TopK returns up to k words starting with prefix, heaviest first. It runs a
+// best-first search guided by the highest weight of every subtree, so only
+// the subtrees that can still contribute are visited.
+func (t *Trie) TopK(prefix string, k int) []Suggestion {
+	n := t.node(prefix)
+	if n == nil || k <= 0 {
+		return nil
+	}
+	var res []Suggestion
+	pq := &candidates{{node: n, path: prefix, weight: n.maxWeight}}
+	for pq.Len() > 0 && len(res) < k {
+		c := heap.Pop(pq).(candidate)
+		if c.isWord {
+			res = append(res, Suggestion{Word: c.word, Weight: c.weight})
+			continue
+		}
+		if c.node.isWordEnd {
+			heap.Push(pq, candidate{path: c.path, word: c.node.word, isWord: true, weight: c.node.weight})
+		}
+		for _, child := range c.node.children {
+			if child != nil {
+				heap.Push(pq, candidate{node: child, path: c.path + string(child.char), weight: child.maxWeight})
+			}
+		}
+	}
+	return res
+}
+
+// Fuzzy returns the words within maxDist Levenshtein edits (insertions,
+// deletions and substitutions of single bytes) of query, in the order of
+// their charIndex slots. Subtrees whose prefix is already too far from
+// every prefix of query are skipped.
+func (t *Trie) Fuzzy(query string, maxDist int) []FuzzyMatch {
+	if maxDist < 0 {
+		return nil
+	}
+	// row[i] is the distance between the current path and query[:i]
+	row := make([]int, len(query)+1)
+	for i := range row {
+		row[i] = i
+	}
+	var res []FuzzyMatch
+	if t.root.isWordEnd && row[len(query)] <= maxDist {
+		res = append(res, FuzzyMatch{Word: "", Distance: row[len(query)]})
+	}
+	for _, c := range t.root.children {
+		if c != nil {
+			t.fuzzy(c, query, row, maxDist, &res)
+		}
+	}
+	return res
+}
+
+func (t *Trie) fuzzy(n *trieNode, query string, prev []int, maxDist int, res *[]FuzzyMatch) {
+	row := make([]int, len(prev))
+	row[0] = prev[0] + 1
+	best := row[0]
+	index := t.charIndex(n.char)
+	for i := 1; i < len(row); i++ {
+		cost := 1
+		if t.charIndex(query[i-1]) == index {
+			cost = 0
+		}
+		row[i] = min(row[i-1]+1, prev[i]+1, prev[i-1]+cost)
+		best = min(best, row[i])
+	}
+	if best > maxDist {
+		return
+	}
+	if d := row[len(query)]; n.isWordEnd && d <= maxDist {
+		*res = append(*res, FuzzyMatch{Word: n.word, Distance: d})
+	}
+	for _, c := range n.children {
+		if c != nil {
+			t.fuzzy(c, query, row, maxDist, res)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package tire
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestTrie_TopK(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	weights := map[string]int{
+		"car": 50, "card": 20, "care": 80, "careful": 10, "cart": 20,
+		"cat": 90, "dog": 100, "do": 5,
+	}
+	for w, weight := range weights {
+		if err := t.InsertWeighted(w, weight); err != nil {
+			t1.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name   string
+		prefix string
+		k      int
+		want   []Suggestion
+	}{
+		{name: "all", prefix: "", k: 3, want: []Suggestion{{"dog", 100}, {"cat", 90}, {"care", 80}}},
+		{name: "prefix", prefix: "car", k: 4, want: []Suggestion{{"care", 80}, {"car", 50}, {"card", 20}, {"cart", 20}}},
+		{name: "fewer than k", prefix: "do", k: 5, want: []Suggestion{{"dog", 100}, {"do", 5}}},
+		{name: "missing prefix", prefix: "x", k: 3, want: nil},
+		{name: "zero k", prefix: "", k: 0, want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := t.TopK(tt.prefix, tt.k); !slices.Equal(got, tt.want) {
+				t1.Errorf("TopK() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// lowering and deleting the heaviest words must update the subtree maxima
+	_ = t.InsertWeighted("care", 1)
+	t.Delete("cat")
+	_ = t.Insert("card")
+	if w, ok := t.Weight("card"); !ok || w != 20 {
+		t1.Errorf("Insert() changed the weight of card to %d", w)
+	}
+	if _, ok := t.Weight("ca"); ok {
+		t1.Error("Weight() of a prefix that is no word")
+	}
+	want := []Suggestion{{"car", 50}, {"card", 20}}
+	if got := t.TopK("c", 2); !slices.Equal(got, want) {
+		t1.Errorf("TopK() after updates = %v, want %v", got, want)
+	}
+}
+
+func TestTrie_TopKRandom(t1 *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	t := InitTrie(4, 'a', nil)
+	weights := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		b := make([]byte, 1+r.IntN(6))
+		for j := range b {
+			b[j] = 'a' + byte(r.IntN(4))
+		}
+		w := string(b)
+		switch r.IntN(4) {
+		case 0:
+			t.Delete(w)
+			delete(weights, w)
+		default:
+			weight := r.IntN(200) - 100
+			_ = t.InsertWeighted(w, weight)
+			weights[w] = weight
+		}
+	}
+	for _, prefix := range []string{"", "a", "bc", "dd"} {
+		var want []Suggestion
+		for w, weight := range weights {
+			if len(w) >= len(prefix) && w[:len(prefix)] == prefix {
+				want = append(want, Suggestion{w, weight})
+			}
+		}
+		slices.SortFunc(want, func(a, b Suggestion) int {
+			return cmp.Or(cmp.Compare(b.Weight, a.Weight), cmp.Compare(a.Word, b.Word))
+		})
+		want = want[:min(len(want), 10)]
+		if got := t.TopK(prefix, 10); !slices.Equal(got, want) {
+			t1.Errorf("TopK(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+}
+
+// levenshtein is the textbook full matrix distance
+func levenshtein(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func TestTrie_Fuzzy(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	words := []string{"", "book", "back", "books", "boo", "cook", "look", "bookkeeper", "brook", "hello"}
+	for _, w := range words {
+		_ = t.Insert(w)
+	}
+	tests := []struct {
+		query   string
+		maxDist int
+		want    []FuzzyMatch
+	}{
+		{query: "book", maxDist: 0, want: []FuzzyMatch{{"book", 0}}},
+		{query: "book", maxDist: 1, want: []FuzzyMatch{{"boo", 1}, {"book", 0}, {"books", 1}, {"brook", 1}, {"cook", 1}, {"look", 1}}},
+		{query: "bok", maxDist: 1, want: []FuzzyMatch{{"boo", 1}, {"book", 1}}},
+		{query: "b", maxDist: 1, want: []FuzzyMatch{{"", 1}}},
+		{query: "xyz", maxDist: 1, want: nil},
+		{query: "book", maxDist: -1, want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.query, func(t1 *testing.T) {
+			got := t.Fuzzy(tt.query, tt.maxDist)
+			if !slices.Equal(got, tt.want) {
+				t1.Errorf("Fuzzy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// compare against the brute force distance on every word
+	for _, q := range []string{"bakc", "helo", "bookeeper", "c"} {
+		var want []FuzzyMatch
+		for _, w := range t.WordsWithPrefix("") {
+			if d := levenshtein(q, w); d <= 2 {
+				want = append(want, FuzzyMatch{w, d})
+			}
+		}
+		if got := t.Fuzzy(q, 2); !slices.Equal(got, want) {
+			t1.Errorf("Fuzzy(%q) = %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestTrie_FuzzyCaseFold(t1 *testing.T) {
+	t := InitTrie(26, 0, CaseFoldIndex)
+	_ = t.Insert("Hello")
+	want := []FuzzyMatch{{"Hello", 1}}
+	if got := t.Fuzzy("HELO", 1); !slices.Equal(got, want) {
+		t1.Errorf("Fuzzy() = %v, want %v", got, want)
+	}
+}
+
+// TestTrie_CaseFoldSpellings checks that words come back as they were
+// inserted, even though spellings share the nodes of their common prefix
+func TestTrie_CaseFoldSpellings(t1 *testing.T) {
+	t := InitTrie(26, 0, CaseFoldIndex)
+	_ = t.InsertWeighted("apple", 2)
+	_ = t.InsertWeighted("APPLY", 1)
+	// the same word in another case keeps its first spelling
+	_ = t.InsertWeighted("Apple", 3)
+	if t.Len() != 2 {
+		t1.Fatalf("Len() = %d, want 2", t.Len())
+	}
+	want := []string{"apple", "APPLY"}
+	for _, prefix := range []string{"", "AP", "ap", "aPpL"} {
+		if got := t.WordsWithPrefix(prefix); !slices.Equal(got, want) {
+			t1.Errorf("WordsWithPrefix(%q) = %v, want %v", prefix, got, want)
+		}
+	}
+	wantTop := []Suggestion{{"apple", 3}, {"APPLY", 1}}
+	if got := t.TopK("AP", 2); !slices.Equal(got, wantTop) {
+		t1.Errorf("TopK() = %v, want %v", got, wantTop)
+	}
+	wantFuzzy := []FuzzyMatch{{"apple", 1}, {"APPLY", 1}}
+	if got := t.Fuzzy("Appl", 1); !slices.Equal(got, wantFuzzy) {
+		t1.Errorf("Fuzzy() = %v, want %v", got, wantFuzzy)
+	}
+	wantMatches := []Match{{Pattern: "APPLY", Start: 2, End: 7}}
+	if got := t.Compile().FindAll("x apply"); !slices.Equal(got, wantMatches) {
+		t1.Errorf("FindAll() = %v, want %v", got, wantMatches)
+	}
+	if got := t.Clone().WordsWithPrefix("A"); !slices.Equal(got, want) {
+		t1.Errorf("clone WordsWithPrefix() = %v, want %v", got, want)
+	}
+	t.Delete("APPLE")
+	if got := t.WordsWithPrefix(""); !slices.Equal(got, []string{"APPLY"}) {
+		t1.Errorf("WordsWithPrefix() after Delete = %v", got)
+	}
+}
+
+func BenchmarkTrie_TopK(b *testing.B) {
+	t := InitTrie(26, 'a', nil)
+	r := rand.New(rand.NewPCG(7, 8))
+	for _, w := range benchWords(100000) {
+		_ = t.InsertWeighted(w, r.IntN(1000000))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.TopK(string(rune('a'+i%26)), 10)
+	}
+}
+
+func BenchmarkTrie_Fuzzy(b *testing.B) {
+	t := InitTrie(26, 'a', nil)
+	words := benchWords(100000)
+	for _, w := range words {
+		_ = t.Insert(w)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t.Fuzzy(words[i%len(words)], 2)
+	}
+}
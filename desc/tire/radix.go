@@ -0,0 +1,238 @@
+package tire
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+type radixNode[V any] struct {
+	// label is the part of the key on the edge leading to the node, it never
+	// starts or ends inside a rune
+	label string
+	// children is sorted by the first rune of their label, which is unique
+	children []*radixNode[V]
+	value    V
+	hasValue bool
+}
+
+// firstRune returns the bytes of the first rune of s, an invalid byte counts
+// as a rune of its own
+func firstRune(s string) string {
+	_, n := utf8.DecodeRuneInString(s)
+	return s[:n]
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b
+// made of whole runes
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) {
+		_, n := utf8.DecodeRuneInString(a[i:])
+		_, m := utf8.DecodeRuneInString(b[i:])
+		if n != m || a[i:i+n] != b[i:i+n] {
+			break
+		}
+		i += n
+	}
+	return i
+}
+
+func (n *radixNode[V]) child(key string) (int, bool) {
+	lead := firstRune(key)
+	return slices.BinarySearchFunc(n.children, lead, func(c *radixNode[V], lead string) int {
+		return strings.Compare(firstRune(c.label), lead)
+	})
+}
+
+// RadixTree maps string keys to values like ValueTrie, but compresses every
+// chain of single-child nodes into one edge labelled with a whole substring.
+// Keys are split on rune boundaries only, so UTF-8 text is never cut inside
+// a character, and a node allocates nothing for absent children. A
+// RadixTree is not safe for concurrent use.
+type RadixTree[V any] struct {
+	root *radixNode[V]
+	size int
+}
+
+// InitRadixTree returns an empty RadixTree
+func InitRadixTree[V any]() *RadixTree[V] {
+	return &RadixTree[V]{root: &radixNode[V]{}}
+}
+
+// Put stores value under key and reports whether key is new
+func (t *RadixTree[V]) Put(key string, value V) bool {
+	current, rest := t.root, key
+	for rest != "" {
+		idx, found := current.child(rest)
+		if !found {
+			leaf := &radixNode[V]{label: rest, value: value, hasValue: true}
+			current.children = slices.Insert(current.children, idx, leaf)
+			t.size++
+			return true
+		}
+		c := current.children[idx]
+		p := commonPrefix(c.label, rest)
+		if p < len(c.label) {
+			// split the edge, the upper half keeps the position of c
+			mid := &radixNode[V]{label: c.label[:p], children: []*radixNode[V]{c}}
+			c.label = c.label[p:]
+			current.children[idx] = mid
+			c = mid
+		}
+		current, rest = c, rest[p:]
+	}
+	added := !current.hasValue
+	if added {
+		t.size++
+	}
+	current.value, current.hasValue = value, true
+	return added
+}
+
+// find returns the node whose path is exactly key
+func (t *RadixTree[V]) find(key string) *radixNode[V] {
+	current, rest := t.root, key
+	for rest != "" {
+		idx, found := current.child(rest)
+		if !found || !strings.HasPrefix(rest, current.children[idx].label) {
+			return nil
+		}
+		current = current.children[idx]
+		rest = rest[len(current.label):]
+	}
+	return current
+}
+
+// Get returns the value stored under key
+func (t *RadixTree[V]) Get(key string) (V, bool) {
+	if n := t.find(key); n != nil && n.hasValue {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key and reports whether it was present. Emptied nodes are
+// removed and a node left with a single child is merged into it, so the tree
+// stays compressed.
+func (t *RadixTree[V]) Delete(key string) bool {
+	var parent, grandparent *radixNode[V]
+	current, rest := t.root, key
+	for rest != "" {
+		idx, found := current.child(rest)
+		if !found || !strings.HasPrefix(rest, current.children[idx].label) {
+			return false
+		}
+		grandparent, parent = parent, current
+		current = current.children[idx]
+		rest = rest[len(current.label):]
+	}
+	if !current.hasValue {
+		return false
+	}
+	var zero V
+	current.value, current.hasValue = zero, false
+	t.size--
+
+	switch {
+	case parent == nil:
+		// the root is never removed or merged
+	case len(current.children) == 0:
+		idx, _ := parent.child(current.label)
+		parent.children = slices.Delete(parent.children, idx, idx+1)
+		if grandparent != nil && !parent.hasValue && len(parent.children) == 1 {
+			idx, _ = grandparent.child(parent.label)
+			grandparent.children[idx] = parent.merge()
+		}
+	case len(current.children) == 1:
+		idx, _ := parent.child(current.label)
+		parent.children[idx] = current.merge()
+	}
+	return true
+}
+
+// merge returns the only child of n with the label of n prepended
+func (n *radixNode[V]) merge() *radixNode[V] {
+	c := n.children[0]
+	c.label = n.label + c.label
+	return c
+}
+
+// LongestPrefixMatch returns the longest stored key that is a prefix of key,
+// together with its value
+func (t *RadixTree[V]) LongestPrefixMatch(key string) (string, V, bool) {
+	var (
+		match string
+		value V
+		ok    bool
+	)
+	current, consumed := t.root, 0
+	for {
+		if current.hasValue {
+			match, value, ok = key[:consumed], current.value, true
+		}
+		rest := key[consumed:]
+		if rest == "" {
+			break
+		}
+		idx, found := current.child(rest)
+		if !found || !strings.HasPrefix(rest, current.children[idx].label) {
+			break
+		}
+		current = current.children[idx]
+		consumed += len(current.label)
+	}
+	return match, value, ok
+}
+
+// Len returns the number of keys
+func (t *RadixTree[V]) Len() int {
+	return t.size
+}
+
+// Walk calls fn for every key and value in lexicographic key order until fn
+// returns false. The order is that of the runes, so it is only meaningful
+// for valid UTF-8 keys.
+func (t *RadixTree[V]) Walk(fn func(key string, value V) bool) {
+	t.root.walk(nil, fn)
+}
+
+// WalkPrefix calls fn for every key starting with prefix, in lexicographic
+// order, until fn returns false. A prefix ending inside a rune matches
+// nothing.
+func (t *RadixTree[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	current, rest := t.root, prefix
+	buf := make([]byte, 0, len(prefix))
+	for rest != "" {
+		idx, found := current.child(rest)
+		if !found {
+			return
+		}
+		current = current.children[idx]
+		switch {
+		case strings.HasPrefix(rest, current.label):
+			rest = rest[len(current.label):]
+		case strings.HasPrefix(current.label, rest) && utf8.RuneStart(current.label[len(rest)]):
+			// the prefix ends inside this edge, on a rune boundary
+			rest = ""
+		default:
+			return
+		}
+		buf = append(buf, current.label...)
+	}
+	current.walk(buf, fn)
+}
+
+// walk calls fn with every key below n, whose path is buf
+func (n *radixNode[V]) walk(buf []byte, fn func(string, V) bool) bool {
+	if n.hasValue && !fn(string(buf), n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walk(append(buf, c.label...), fn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,335 @@
+package tire
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// checkRadix verifies that every edge is cut on rune boundaries and that no
+// node other than the root could be merged into its child
+func checkRadix[V any](t *testing.T, n *radixNode[V], root bool) {
+	t.Helper()
+	if !root {
+		if n.label == "" {
+			t.Fatal("empty edge label")
+		}
+		if !n.hasValue && len(n.children) < 2 {
+			t.Fatalf("node %q without value has %d children", n.label, len(n.children))
+		}
+	}
+	for i, c := range n.children {
+		if i > 0 && firstRune(n.children[i-1].label) >= firstRune(c.label) {
+			t.Fatalf("children %q and %q out of order", n.children[i-1].label, c.label)
+		}
+		if !utf8.RuneStart(c.label[0]) {
+			t.Fatalf("edge %q cuts a rune", c.label)
+		}
+		checkRadix(t, c, false)
+	}
+}
+
+func TestRadixTree_PutGet(t1 *testing.T) {
+	t := InitRadixTree[int]()
+	keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "", "r"}
+	for i, k := range keys {
+		if !t.Put(k, i) {
+			t1.Fatalf("Put(%q) reported an existing key", k)
+		}
+	}
+	if t.Put("ruber", 100) || t.Len() != len(keys) {
+		t1.Fatalf("Put() of an existing key changed Len() to %d", t.Len())
+	}
+	checkRadix(t1, t.root, true)
+	tests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{key: "romanus", want: 1, ok: true},
+		{key: "ruber", want: 100, ok: true},
+		{key: "", want: 7, ok: true},
+		{key: "r", want: 8, ok: true},
+		{key: "rom", ok: false},
+		{key: "romanusx", ok: false},
+		{key: "x", ok: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			if got, ok := t.Get(tt.key); got != tt.want || ok != tt.ok {
+				t1.Errorf("Get() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRadixTree_Unicode(t1 *testing.T) {
+	t := InitRadixTree[string]()
+	// é and è share their first UTF-8 byte, so must not be split after it
+	for _, k := range []string{"café", "cafè", "日本", "日本語", "日曜日", "naïve", "\xc3", "\xc3a"} {
+		t.Put(k, strings.ToUpper(k))
+	}
+	checkRadix(t1, t.root, true)
+	for _, k := range []string{"café", "cafè", "日本", "日本語", "日曜日", "\xc3", "\xc3a"} {
+		if got, ok := t.Get(k); !ok || got != strings.ToUpper(k) {
+			t1.Errorf("Get(%q) = %q, %v", k, got, ok)
+		}
+	}
+	if _, ok := t.Get("caf\xc3"); ok {
+		t1.Error("a key ending inside a rune was found")
+	}
+
+	var got []string
+	t.WalkPrefix("日", func(key, _ string) bool {
+		got = append(got, key)
+		return true
+	})
+	if want := []string{"日曜日", "日本", "日本語"}; !slices.Equal(got, want) {
+		t1.Errorf("WalkPrefix() = %v, want %v", got, want)
+	}
+	got = nil
+	t.WalkPrefix("caf\xc3", func(key, _ string) bool {
+		got = append(got, key)
+		return true
+	})
+	if got != nil {
+		t1.Errorf("WalkPrefix() of a prefix ending inside a rune = %v", got)
+	}
+
+	// the same inside a single edge, which is not split at that byte
+	single := InitRadixTree[int]()
+	single.Put("aé", 1)
+	got = nil
+	single.WalkPrefix("a\xc3", func(key string, _ int) bool {
+		got = append(got, key)
+		return true
+	})
+	if got != nil {
+		t1.Errorf("WalkPrefix() of a prefix ending inside a rune of an edge = %v", got)
+	}
+	single.WalkPrefix("a", func(key string, _ int) bool {
+		got = append(got, key)
+		return true
+	})
+	if !slices.Equal(got, []string{"aé"}) {
+		t1.Errorf("WalkPrefix(\"a\") = %v, want [aé]", got)
+	}
+}
+
+func TestRadixTree_Delete(t1 *testing.T) {
+	t := InitRadixTree[int]()
+	for i, k := range []string{"test", "team", "tea", "toast", "", "te"} {
+		t.Put(k, i)
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "t", want: false},
+		{key: "teams", want: false},
+		{key: "xyz", want: false},
+		{key: "te", want: true},
+		{key: "te", want: false},
+		{key: "tea", want: true},
+		{key: "test", want: true},
+		{key: "", want: true},
+		{key: "team", want: true},
+		{key: "toast", want: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			if got := t.Delete(tt.key); got != tt.want {
+				t1.Errorf("Delete() = %v, want %v", got, tt.want)
+			}
+			if _, ok := t.Get(tt.key); ok {
+				t1.Errorf("%q still present", tt.key)
+			}
+			checkRadix(t1, t.root, true)
+		})
+	}
+	if t.Len() != 0 || len(t.root.children) != 0 {
+		t1.Errorf("Len() = %d, %d children left", t.Len(), len(t.root.children))
+	}
+}
+
+func TestRadixTree_LongestPrefixMatch(t1 *testing.T) {
+	t := InitRadixTree[int]()
+	t.Put("/api", 1)
+	t.Put("/api/users", 2)
+	t.Put("/api/users/ü", 3)
+	tests := []struct {
+		key   string
+		match string
+		want  int
+		ok    bool
+	}{
+		{key: "/api/users/ü/42", match: "/api/users/ü", want: 3, ok: true},
+		{key: "/api/users/u", match: "/api/users", want: 2, ok: true},
+		{key: "/api/user", match: "/api", want: 1, ok: true},
+		{key: "/ap", ok: false},
+		{key: "", ok: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			match, got, ok := t.LongestPrefixMatch(tt.key)
+			if match != tt.match || got != tt.want || ok != tt.ok {
+				t1.Errorf("LongestPrefixMatch() = %q, %v, %v, want %q, %v, %v",
+					match, got, ok, tt.match, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRadixTree_Walk(t1 *testing.T) {
+	t := InitRadixTree[int]()
+	keys := []string{"b", "abc", "a", "ab", "ac", "é", "e"}
+	for i, k := range keys {
+		t.Put(k, i)
+	}
+	var got []string
+	t.Walk(func(key string, value int) bool {
+		if keys[value] != key {
+			t1.Errorf("key %q has value %d", key, value)
+		}
+		got = append(got, key)
+		return true
+	})
+	if want := []string{"a", "ab", "abc", "ac", "b", "e", "é"}; !slices.Equal(got, want) {
+		t1.Errorf("Walk() = %v, want %v", got, want)
+	}
+	got = nil
+	t.WalkPrefix("a", func(key string, _ int) bool {
+		got = append(got, key)
+		return len(got) < 2
+	})
+	if want := []string{"a", "ab"}; !slices.Equal(got, want) {
+		t1.Errorf("WalkPrefix() = %v, want %v", got, want)
+	}
+	for _, prefix := range []string{"x", "abd", "abcd"} {
+		t.WalkPrefix(prefix, func(key string, _ int) bool {
+			t1.Errorf("WalkPrefix(%q) found %q", prefix, key)
+			return true
+		})
+	}
+}
+
+// TestRadixTree_Random compares the tree against a map under random puts and
+// deletes of keys drawn from a small alphabet with multi-byte runes
+func TestRadixTree_Random(t1 *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	alphabet := []rune("abé日")
+	key := func() string {
+		var sb strings.Builder
+		for n := r.IntN(5); n > 0; n-- {
+			sb.WriteRune(alphabet[r.IntN(len(alphabet))])
+		}
+		return sb.String()
+	}
+	t := InitRadixTree[int]()
+	want := map[string]int{}
+	for i := 0; i < 5000; i++ {
+		k := key()
+		if r.IntN(3) == 0 {
+			_, ok := want[k]
+			if got := t.Delete(k); got != ok {
+				t1.Fatalf("Delete(%q) = %v, want %v", k, got, ok)
+			}
+			delete(want, k)
+		} else {
+			_, ok := want[k]
+			if got := t.Put(k, i); got == ok {
+				t1.Fatalf("Put(%q) = %v, want %v", k, got, !ok)
+			}
+			want[k] = i
+		}
+	}
+	checkRadix(t1, t.root, true)
+	if t.Len() != len(want) {
+		t1.Fatalf("Len() = %d, want %d", t.Len(), len(want))
+	}
+	var keys []string
+	t.Walk(func(k string, v int) bool {
+		if want[k] != v {
+			t1.Errorf("%q = %d, want %d", k, v, want[k])
+		}
+		keys = append(keys, k)
+		return true
+	})
+	if sorted := slices.Sorted(maps.Keys(want)); !slices.Equal(keys, sorted) {
+		t1.Errorf("Walk() = %v, want %v", keys, sorted)
+	}
+}
+
+// benchWords returns n random lower case words sharing many prefixes, as
+// natural language vocabularies do
+func benchWords(n int) []string {
+	r := rand.New(rand.NewPCG(3, 4))
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, 4+r.IntN(8))
+		for j := range b {
+			b[j] = 'a' + byte(r.IntN(26))
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+// the memory benchmarks build the whole structure per iteration, so B/op
+// is the memory needed to hold the words
+func BenchmarkMemory(b *testing.B) {
+	for _, n := range []int{1000, 100000} {
+		words := benchWords(n)
+		b.Run(fmt.Sprintf("Trie/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				t := InitTrie(26, 'a', nil)
+				for _, w := range words {
+					_ = t.Insert(w)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("ValueTrie/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				t := InitValueTrie[struct{}]()
+				for _, w := range words {
+					t.Put(w, struct{}{})
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("RadixTree/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				t := InitRadixTree[struct{}]()
+				for _, w := range words {
+					t.Put(w, struct{}{})
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	words := benchWords(100000)
+	trie := InitTrie(26, 'a', nil)
+	radix := InitRadixTree[struct{}]()
+	for _, w := range words {
+		_ = trie.Insert(w)
+		radix.Put(w, struct{}{})
+	}
+	b.Run("Trie", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			trie.Contains(words[i%len(words)])
+		}
+	})
+	b.Run("RadixTree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			radix.Get(words[i%len(words)])
+		}
+	})
+}
@@ -1,80 +1,244 @@
 package tire
 
 import (
+	"math"
+
 	"gopkg.in/errgo.v2/errors"
 )
 
 type trieNode struct {
-	children  []*trieNode
+	children []*trieNode
+	// char is the byte that led to the node
+	char      byte
 	isWordEnd bool
+	// word is the word ending at the node as it was first inserted. With a
+	// charIndex that maps several bytes to one slot, such as CaseFoldIndex,
+	// it can differ from the chars on the path, which may come from other
+	// spellings.
+	word string
+	// weight is the weight of the word ending here, maxWeight the highest
+	// weight of any word in the subtree, which guides TopK
+	weight    int
+	maxWeight int
 }
 
-type trie struct {
+// Trie is a prefix tree over an alphabet of cnt bytes. charIndex maps a
+// byte to its child slot in [0, cnt), or -1 when the byte is not part of
+// the alphabet; by default the alphabet is the cnt bytes from headChar on.
+// A Trie is not safe for concurrent use.
+type Trie struct {
 	root      *trieNode
 	headChar  byte
 	cnt       int
 	charIndex func(byte) int
+	size      int
 }
 
 var (
 	ErrInvalidChar = errors.New("invalid char")
 )
 
-func InitTrie(cnt int, headChar byte, charIndex func(byte) int) *trie {
-	t := &trie{
+func InitTrie(cnt int, headChar byte, charIndex func(byte) int) *Trie {
+	t := &Trie{
 		root: &trieNode{
-			children: make([]*trieNode, cnt, cnt),
+			children:  make([]*trieNode, cnt, cnt),
+			maxWeight: math.MinInt,
 		},
 		headChar:  headChar,
 		cnt:       cnt,
 		charIndex: charIndex,
 	}
 	if t.charIndex == nil {
-		t.charIndex = t.defaultIndex
+		// a closure over the parameters, not over t, so that clones and
+		// Matchers do not keep t alive
+		t.charIndex = func(char byte) int {
+			return rangeIndex(char, headChar, cnt)
+		}
 	}
 	return t
 }
 
-func (t *trie) insert(word string) error {
-	wordLength := len(word)
+// rangeIndex maps the cnt bytes from headChar on to [0, cnt)
+func rangeIndex(char, headChar byte, cnt int) int {
+	idx := int(char) - int(headChar)
+	// confirm char must be in range
+	if idx >= 0 && idx < cnt {
+		return idx
+	}
+	return -1
+}
+
+// Insert adds word to the trie. It returns ErrInvalidChar, leaving the trie
+// unchanged, if word holds a byte outside the alphabet. A new word gets a
+// weight of 0, an existing one keeps its weight.
+func (t *Trie) Insert(word string) error {
+	return t.insert(word, 0, false)
+}
+
+// InsertWeighted adds word with the given weight, replacing the weight of
+// an existing word
+func (t *Trie) InsertWeighted(word string, weight int) error {
+	return t.insert(word, weight, true)
+}
+
+func (t *Trie) insert(word string, weight int, setWeight bool) error {
+	for i := 0; i < len(word); i++ {
+		if t.charIndex(word[i]) == -1 {
+			return ErrInvalidChar
+		}
+	}
+	path := make([]*trieNode, 0, len(word)+1)
 	current := t.root
-	for i := 0; i < wordLength; i++ {
-		if index := t.charIndex(word[i]); index != -1 {
-			if current.children[index] == nil {
-				current.children[index] = &trieNode{
-					children: make([]*trieNode, t.cnt, t.cnt),
-				}
+	for i := 0; i < len(word); i++ {
+		path = append(path, current)
+		index := t.charIndex(word[i])
+		if current.children[index] == nil {
+			current.children[index] = &trieNode{
+				children:  make([]*trieNode, t.cnt, t.cnt),
+				char:      word[i],
+				maxWeight: math.MinInt,
 			}
-			current = current.children[index]
-		} else {
-			return ErrInvalidChar
 		}
+		current = current.children[index]
+	}
+	path = append(path, current)
+	if !current.isWordEnd {
+		current.isWordEnd = true
+		current.word = word
+		current.weight = 0
+		t.size++
+		setWeight = true
+	}
+	if !setWeight {
+		return nil
+	}
+	lowered := weight < current.weight
+	current.weight = weight
+	if lowered {
+		updateMaxWeight(path)
+		return nil
+	}
+	for _, n := range path {
+		n.maxWeight = max(n.maxWeight, weight)
 	}
-	current.isWordEnd = true
 	return nil
 }
 
-func (t *trie) defaultIndex(char byte) int {
-	idx := int(char - t.headChar)
-	// confirm char must be in range
-	if idx >= 0 && idx < cap(t.root.children) {
-		return idx
+// updateMaxWeight recomputes maxWeight bottom up along path after a weight
+// went down or a word went away
+func updateMaxWeight(path []*trieNode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		n.maxWeight = math.MinInt
+		if n.isWordEnd {
+			n.maxWeight = n.weight
+		}
+		for _, c := range n.children {
+			if c != nil {
+				n.maxWeight = max(n.maxWeight, c.maxWeight)
+			}
+		}
 	}
-	return -1
 }
 
-func (t *trie) find(word string) bool {
-	wordLength := len(word)
+// node returns the node reached by prefix, or nil
+func (t *Trie) node(prefix string) *trieNode {
 	current := t.root
-	for i := 0; i < wordLength; i++ {
-		if index := t.charIndex(word[i]); index != -1 {
-			if current.children[index] == nil {
-				return false
-			}
-			current = current.children[index]
-		} else {
+	for i := 0; i < len(prefix) && current != nil; i++ {
+		index := t.charIndex(prefix[i])
+		if index == -1 {
+			return nil
+		}
+		current = current.children[index]
+	}
+	return current
+}
+
+// Contains reports whether word was inserted
+func (t *Trie) Contains(word string) bool {
+	n := t.node(word)
+	return n != nil && n.isWordEnd
+}
+
+// HasPrefix reports whether some inserted word starts with prefix
+func (t *Trie) HasPrefix(prefix string) bool {
+	// the root exists even when the trie holds no word
+	if prefix == "" {
+		return t.size > 0
+	}
+	return t.node(prefix) != nil
+}
+
+// Delete removes word and reports whether it was present. Nodes that no
+// longer lead to any word are pruned.
+func (t *Trie) Delete(word string) bool {
+	path := make([]*trieNode, 0, len(word)+1)
+	current := t.root
+	for i := 0; i < len(word) && current != nil; i++ {
+		path = append(path, current)
+		index := t.charIndex(word[i])
+		if index == -1 {
 			return false
 		}
+		current = current.children[index]
+	}
+	if current == nil || !current.isWordEnd {
+		return false
+	}
+	current.isWordEnd = false
+	current.word = ""
+	t.size--
+	// walk back up, cutting every node that became a dead end
+	end := len(path)
+	for ; end > 0 && current.isLeaf(); end-- {
+		path[end-1].children[t.charIndex(word[end-1])] = nil
+		current = path[end-1]
+	}
+	updateMaxWeight(append(path[:end], current))
+	return true
+}
+
+func (n *trieNode) isLeaf() bool {
+	if n.isWordEnd {
+		return false
+	}
+	for _, c := range n.children {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of words in the trie
+func (t *Trie) Len() int {
+	return t.size
+}
+
+// WordsWithPrefix returns the words starting with prefix in the order of
+// their charIndex slots, which is lexicographic order for the default
+// alphabet. Every word is returned as it was first inserted, so with
+// CaseFoldIndex WordsWithPrefix("AP") may return "apple".
+func (t *Trie) WordsWithPrefix(prefix string) []string {
+	n := t.node(prefix)
+	if n == nil {
+		return nil
+	}
+	var words []string
+	n.walk(func(word string) {
+		words = append(words, word)
+	})
+	return words
+}
+
+// walk calls fn with every word below n
+func (n *trieNode) walk(fn func(string)) {
+	if n.isWordEnd {
+		fn(n.word)
+	}
+	for _, c := range n.children {
+		if c != nil {
+			c.walk(fn)
+		}
 	}
-	return current.isWordEnd
 }
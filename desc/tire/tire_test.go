@@ -1,8 +1,10 @@
 package tire
 
 import (
-	"github.com/go-test/deep"
+	"slices"
 	"testing"
+
+	"github.com/go-test/deep"
 )
 
 func TestInitTrie(t *testing.T) {
@@ -14,7 +16,7 @@ func TestInitTrie(t *testing.T) {
 	tests := []struct {
 		name string
 		args args
-		want *trie
+		want *Trie
 	}{
 		{
 			name: "all is ok",
@@ -22,7 +24,7 @@ func TestInitTrie(t *testing.T) {
 				cnt:      26,
 				headChar: 'a',
 			},
-			want: &trie{
+			want: &Trie{
 				root: &trieNode{
 					children: make([]*trieNode, 26),
 				},
@@ -41,7 +43,7 @@ func TestInitTrie(t *testing.T) {
 	}
 }
 
-func Test_trie_defaultIndex(t1 *testing.T) {
+func Test_rangeIndex(t1 *testing.T) {
 	type fields struct {
 		root      *trieNode
 		headChar  byte
@@ -67,15 +69,14 @@ func Test_trie_defaultIndex(t1 *testing.T) {
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
-			t := InitTrie(26, 'a', nil)
-			if got := t.defaultIndex(tt.args.char); got != tt.want {
-				t1.Errorf("defaultIndex() = %v, want %v", got, tt.want)
+			if got := rangeIndex(tt.args.char, 'a', 26); got != tt.want {
+				t1.Errorf("rangeIndex() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_trie_find(t1 *testing.T) {
+func TestTrie_Contains(t1 *testing.T) {
 
 	type args struct {
 		word string
@@ -110,15 +111,15 @@ func Test_trie_find(t1 *testing.T) {
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
 			t := InitTrie(26, 'a', nil)
-			_ = t.insert("hello")
-			if got := t.find(tt.args.word); got != tt.want {
-				t1.Errorf("find() = %v, want %v", got, tt.want)
+			_ = t.Insert("hello")
+			if got := t.Contains(tt.args.word); got != tt.want {
+				t1.Errorf("Contains() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-func Test_trie_insert(t1 *testing.T) {
+func TestTrie_Insert(t1 *testing.T) {
 	type args struct {
 		word string
 	}
@@ -152,9 +153,137 @@ func Test_trie_insert(t1 *testing.T) {
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
 			t := InitTrie(26, 'a', nil)
-			if err := t.insert(tt.args.word); (err != nil) != tt.wantErr {
-				t1.Errorf("insert() error = %v, wantErr %v", err, tt.wantErr)
+			if err := t.Insert(tt.args.word); (err != nil) != tt.wantErr {
+				t1.Errorf("Insert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrie_HasPrefix(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	_ = t.Insert("hello")
+	_ = t.Insert("help")
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{prefix: "", want: true},
+		{prefix: "hel", want: true},
+		{prefix: "hello", want: true},
+		{prefix: "helloo", want: false},
+		{prefix: "w", want: false},
+		{prefix: "H", want: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.prefix, func(t1 *testing.T) {
+			if got := t.HasPrefix(tt.prefix); got != tt.want {
+				t1.Errorf("HasPrefix() = %v, want %v", got, tt.want)
 			}
 		})
 	}
+
+	empty := InitTrie(26, 'a', nil)
+	if empty.HasPrefix("") {
+		t1.Error("HasPrefix(\"\") = true on an empty trie")
+	}
+	_ = empty.Insert("")
+	if !empty.HasPrefix("") {
+		t1.Error("HasPrefix(\"\") = false with the empty word inserted")
+	}
+}
+
+func TestTrie_Delete(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	for _, w := range []string{"he", "hello", "help", "world"} {
+		_ = t.Insert(w)
+	}
+	_ = t.Insert("he")
+	if t.Len() != 4 {
+		t1.Fatalf("Len() = %d, want 4", t.Len())
+	}
+	tests := []struct {
+		word       string
+		want       bool
+		prefix     string
+		wantPrefix bool
+	}{
+		{word: "hel", want: false, prefix: "hel", wantPrefix: true},
+		{word: "x1", want: false, prefix: "x", wantPrefix: false},
+		{word: "hello", want: true, prefix: "hell", wantPrefix: false},
+		{word: "hello", want: false, prefix: "help", wantPrefix: true},
+		{word: "help", want: true, prefix: "hel", wantPrefix: false},
+		// he is still a word, so its nodes stay
+		{word: "world", want: true, prefix: "w", wantPrefix: false},
+		{word: "he", want: true, prefix: "h", wantPrefix: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.word, func(t1 *testing.T) {
+			if got := t.Delete(tt.word); got != tt.want {
+				t1.Errorf("Delete() = %v, want %v", got, tt.want)
+			}
+			if t.Contains(tt.word) {
+				t1.Errorf("%q still present", tt.word)
+			}
+			if got := t.HasPrefix(tt.prefix); got != tt.wantPrefix {
+				t1.Errorf("HasPrefix(%q) = %v, want %v", tt.prefix, got, tt.wantPrefix)
+			}
+		})
+	}
+	if t.Len() != 0 || !t.root.isLeaf() {
+		t1.Errorf("Len() = %d, nodes left after deleting everything", t.Len())
+	}
+	if t.HasPrefix("") {
+		t1.Error("HasPrefix(\"\") = true after deleting everything")
+	}
+}
+
+func TestTrie_WordsWithPrefix(t1 *testing.T) {
+	t := InitTrie(26, 'a', nil)
+	words := []string{"team", "tea", "ten", "to", "inn", "in", "a"}
+	for _, w := range words {
+		_ = t.Insert(w)
+	}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{prefix: "", want: []string{"a", "in", "inn", "tea", "team", "ten", "to"}},
+		{prefix: "te", want: []string{"tea", "team", "ten"}},
+		{prefix: "tea", want: []string{"tea", "team"}},
+		{prefix: "x", want: nil},
+		{prefix: "T", want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.prefix, func(t1 *testing.T) {
+			if got := t.WordsWithPrefix(tt.prefix); !slices.Equal(got, tt.want) {
+				t1.Errorf("WordsWithPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrie_charIndex(t1 *testing.T) {
+	// digits followed by lower case letters
+	index := func(c byte) int {
+		switch {
+		case c >= '0' && c <= '9':
+			return int(c - '0')
+		case c >= 'a' && c <= 'z':
+			return int(c-'a') + 10
+		}
+		return -1
+	}
+	t := InitTrie(36, 0, index)
+	for _, w := range []string{"b2", "a1", "1a", "b10"} {
+		if err := t.Insert(w); err != nil {
+			t1.Fatal(err)
+		}
+	}
+	if err := t.Insert("a-1"); err == nil || t.HasPrefix("a-") {
+		t1.Error("Insert() accepted a byte outside the alphabet")
+	}
+	if got, want := t.WordsWithPrefix(""), []string{"1a", "a1", "b10", "b2"}; !slices.Equal(got, want) {
+		t1.Errorf("WordsWithPrefix() = %v, want %v", got, want)
+	}
 }
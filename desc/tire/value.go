@@ -0,0 +1,161 @@
+package tire
+
+import (
+	"slices"
+)
+
+type valueNode[V any] struct {
+	// children is sorted by char
+	children []*valueNode[V]
+	char     byte
+	value    V
+	hasValue bool
+}
+
+func (n *valueNode[V]) child(c byte) (int, bool) {
+	return slices.BinarySearchFunc(n.children, c, func(e *valueNode[V], c byte) int {
+		return int(e.char) - int(c)
+	})
+}
+
+// ValueTrie maps string keys to values and answers prefix queries, such as
+// finding the value stored for the longest prefix of a key. Unlike Trie it
+// accepts any byte, and it only allocates the children that exist. A
+// ValueTrie is not safe for concurrent use.
+type ValueTrie[V any] struct {
+	root *valueNode[V]
+	size int
+}
+
+// InitValueTrie returns an empty ValueTrie
+func InitValueTrie[V any]() *ValueTrie[V] {
+	return &ValueTrie[V]{root: &valueNode[V]{}}
+}
+
+// Put stores value under key and reports whether key is new
+func (t *ValueTrie[V]) Put(key string, value V) bool {
+	current := t.root
+	for i := 0; i < len(key); i++ {
+		idx, found := current.child(key[i])
+		if !found {
+			current.children = slices.Insert(current.children, idx, &valueNode[V]{char: key[i]})
+		}
+		current = current.children[idx]
+	}
+	added := !current.hasValue
+	if added {
+		t.size++
+	}
+	current.value, current.hasValue = value, true
+	return added
+}
+
+func (t *ValueTrie[V]) node(key string) *valueNode[V] {
+	current := t.root
+	for i := 0; i < len(key); i++ {
+		idx, found := current.child(key[i])
+		if !found {
+			return nil
+		}
+		current = current.children[idx]
+	}
+	return current
+}
+
+// Get returns the value stored under key
+func (t *ValueTrie[V]) Get(key string) (V, bool) {
+	if n := t.node(key); n != nil && n.hasValue {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key and reports whether it was present. Nodes that no
+// longer lead to any value are pruned.
+func (t *ValueTrie[V]) Delete(key string) bool {
+	path := make([]*valueNode[V], 0, len(key)+1)
+	current := t.root
+	for i := 0; i < len(key); i++ {
+		path = append(path, current)
+		idx, found := current.child(key[i])
+		if !found {
+			return false
+		}
+		current = current.children[idx]
+	}
+	if !current.hasValue {
+		return false
+	}
+	var zero V
+	current.value, current.hasValue = zero, false
+	t.size--
+	for i := len(path) - 1; i >= 0 && !current.hasValue && len(current.children) == 0; i-- {
+		idx, _ := path[i].child(key[i])
+		path[i].children = slices.Delete(path[i].children, idx, idx+1)
+		current = path[i]
+	}
+	return true
+}
+
+// LongestPrefixMatch returns the longest stored key that is a prefix of key,
+// together with its value
+func (t *ValueTrie[V]) LongestPrefixMatch(key string) (string, V, bool) {
+	var (
+		match string
+		value V
+		ok    bool
+	)
+	current := t.root
+	for i := 0; ; i++ {
+		if current.hasValue {
+			match, value, ok = key[:i], current.value, true
+		}
+		if i == len(key) {
+			break
+		}
+		idx, found := current.child(key[i])
+		if !found {
+			break
+		}
+		current = current.children[idx]
+	}
+	return match, value, ok
+}
+
+// Len returns the number of keys
+func (t *ValueTrie[V]) Len() int {
+	return t.size
+}
+
+// Walk calls fn for every key and value in lexicographic key order until fn
+// returns false
+func (t *ValueTrie[V]) Walk(fn func(key string, value V) bool) {
+	t.WalkPrefix("", fn)
+}
+
+// WalkPrefix calls fn for every key starting with prefix, in lexicographic
+// order, until fn returns false
+func (t *ValueTrie[V]) WalkPrefix(prefix string, fn func(key string, value V) bool) {
+	n := t.node(prefix)
+	if n == nil {
+		return
+	}
+	buf := []byte(prefix)
+	n.walk(&buf, fn)
+}
+
+func (n *valueNode[V]) walk(buf *[]byte, fn func(string, V) bool) bool {
+	if n.hasValue && !fn(string(*buf), n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		*buf = append(*buf, c.char)
+		ok := c.walk(buf, fn)
+		*buf = (*buf)[:len(*buf)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
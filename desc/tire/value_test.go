@@ -0,0 +1,149 @@
+package tire
+
+import (
+	"slices"
+	"testing"
+)
+
+type walked struct {
+	key   string
+	value int
+}
+
+func collect(t *ValueTrie[int], prefix string) []walked {
+	var got []walked
+	t.WalkPrefix(prefix, func(key string, value int) bool {
+		got = append(got, walked{key, value})
+		return true
+	})
+	return got
+}
+
+func TestValueTrie_PutGet(t1 *testing.T) {
+	t := InitValueTrie[int]()
+	if !t.Put("user.created", 1) || !t.Put("user", 2) || !t.Put("", 3) {
+		t1.Fatal("Put() of a new key reported false")
+	}
+	if t.Put("user", 4) {
+		t1.Error("Put() of an existing key reported true")
+	}
+	tests := []struct {
+		key  string
+		want int
+		ok   bool
+	}{
+		{key: "user", want: 4, ok: true},
+		{key: "user.created", want: 1, ok: true},
+		{key: "", want: 3, ok: true},
+		{key: "user.", want: 0, ok: false},
+		{key: "users", want: 0, ok: false},
+		{key: "ünïcode", want: 0, ok: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			if got, ok := t.Get(tt.key); got != tt.want || ok != tt.ok {
+				t1.Errorf("Get() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+	if t.Len() != 3 {
+		t1.Errorf("Len() = %d, want 3", t.Len())
+	}
+}
+
+func TestValueTrie_Delete(t1 *testing.T) {
+	t := InitValueTrie[int]()
+	for i, k := range []string{"a", "ab", "abc", "b"} {
+		t.Put(k, i)
+	}
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "abcd", want: false},
+		{key: "x", want: false},
+		{key: "abc", want: true},
+		{key: "abc", want: false},
+		{key: "a", want: true},
+		{key: "ab", want: true},
+		{key: "b", want: true},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			if got := t.Delete(tt.key); got != tt.want {
+				t1.Errorf("Delete() = %v, want %v", got, tt.want)
+			}
+			if _, ok := t.Get(tt.key); ok {
+				t1.Errorf("%q still present", tt.key)
+			}
+		})
+	}
+	if t.Len() != 0 || len(t.root.children) != 0 {
+		t1.Errorf("Len() = %d, %d children left", t.Len(), len(t.root.children))
+	}
+}
+
+func TestValueTrie_LongestPrefixMatch(t1 *testing.T) {
+	t := InitValueTrie[int]()
+	t.Put("order", 1)
+	t.Put("order.paid", 2)
+	t.Put("order.paid.refund", 3)
+	tests := []struct {
+		key   string
+		match string
+		want  int
+		ok    bool
+	}{
+		{key: "order.paid.refund.partial", match: "order.paid.refund", want: 3, ok: true},
+		{key: "order.paid.re", match: "order.paid", want: 2, ok: true},
+		{key: "order.created", match: "order", want: 1, ok: true},
+		{key: "order", match: "order", want: 1, ok: true},
+		{key: "ord", ok: false},
+		{key: "user.created", ok: false},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.key, func(t1 *testing.T) {
+			match, got, ok := t.LongestPrefixMatch(tt.key)
+			if match != tt.match || got != tt.want || ok != tt.ok {
+				t1.Errorf("LongestPrefixMatch() = %q, %v, %v, want %q, %v, %v",
+					match, got, ok, tt.match, tt.want, tt.ok)
+			}
+		})
+	}
+
+	t.Put("", 0)
+	if match, got, ok := t.LongestPrefixMatch("user"); match != "" || got != 0 || !ok {
+		t1.Errorf("the empty key should match everything, got %q, %v, %v", match, got, ok)
+	}
+}
+
+func TestValueTrie_Walk(t1 *testing.T) {
+	t := InitValueTrie[int]()
+	for i, k := range []string{"b", "a", "ab", "abc", "ac", "é"} {
+		t.Put(k, i)
+	}
+	want := []walked{{"a", 1}, {"ab", 2}, {"abc", 3}, {"ac", 4}, {"b", 0}, {"é", 5}}
+	var got []walked
+	t.Walk(func(key string, value int) bool {
+		got = append(got, walked{key, value})
+		return true
+	})
+	if !slices.Equal(got, want) {
+		t1.Errorf("Walk() = %v, want %v", got, want)
+	}
+	if got := collect(t, "ab"); !slices.Equal(got, want[1:3]) {
+		t1.Errorf("WalkPrefix() = %v, want %v", got, want[1:3])
+	}
+	if got := collect(t, "z"); got != nil {
+		t1.Errorf("WalkPrefix() of a missing prefix = %v", got)
+	}
+
+	n := 0
+	t.Walk(func(string, int) bool {
+		n++
+		return n < 3
+	})
+	if n != 3 {
+		t1.Errorf("Walk() did not stop, n = %d", n)
+	}
+}
@@ -0,0 +1,96 @@
+package union_find
+
+// DisjointSet is a union-find over arbitrary comparable keys. Keys are added
+// on first use by Add or Union; a key that was never added is a group of
+// its own. It maps keys to the indexes of a UnionFind, so it keeps the same
+// union by size and path halving.
+type DisjointSet[K comparable] struct {
+	uf    *UnionFind
+	index map[K]int
+	// keys holds the key of every index, in the order they were added
+	keys []K
+}
+
+// InitDisjointSet returns an empty DisjointSet
+func InitDisjointSet[K comparable]() *DisjointSet[K] {
+	return &DisjointSet[K]{uf: InitUnionFind(0), index: map[K]int{}}
+}
+
+// idx returns the index of x, adding x if needed
+func (d *DisjointSet[K]) idx(x K) int {
+	if i, ok := d.index[x]; ok {
+		return i
+	}
+	i := d.uf.add()
+	d.index[x] = i
+	d.keys = append(d.keys, x)
+	return i
+}
+
+// Add adds keys that are not present yet, each as a group of its own
+func (d *DisjointSet[K]) Add(keys ...K) {
+	for _, k := range keys {
+		d.idx(k)
+	}
+}
+
+// Has reports whether x was added
+func (d *DisjointSet[K]) Has(x K) bool {
+	_, ok := d.index[x]
+	return ok
+}
+
+// Find returns the representative key of the group of x
+func (d *DisjointSet[K]) Find(x K) K {
+	i, ok := d.index[x]
+	if !ok {
+		return x
+	}
+	return d.keys[d.uf.find(i)]
+}
+
+// Union merges the groups of x and y, adding them if needed, and reports
+// whether they were separate
+func (d *DisjointSet[K]) Union(x, y K) bool {
+	return d.uf.union(d.idx(x), d.idx(y))
+}
+
+// Connected reports whether x and y are in the same group
+func (d *DisjointSet[K]) Connected(x, y K) bool {
+	if x == y {
+		return true
+	}
+	i, ok := d.index[x]
+	j, ok2 := d.index[y]
+	return ok && ok2 && d.uf.find(i) == d.uf.find(j)
+}
+
+// Size returns the number of keys in the group of x
+func (d *DisjointSet[K]) Size(x K) int {
+	i, ok := d.index[x]
+	if !ok {
+		return 1
+	}
+	return d.uf.rank[d.uf.find(i)]
+}
+
+// Count returns the number of groups among the added keys
+func (d *DisjointSet[K]) Count() int {
+	return d.uf.count
+}
+
+// Len returns the number of added keys
+func (d *DisjointSet[K]) Len() int {
+	return len(d.keys)
+}
+
+// Groups returns the members of every group keyed by its representative,
+// members are in the order they were added
+func (d *DisjointSet[K]) Groups() map[K][]K {
+	groups := make(map[K][]K, d.uf.count)
+	for i, k := range d.keys {
+		root := d.keys[d.uf.find(i)]
+		groups[root] = append(groups[root], k)
+	}
+	return groups
+}
@@ -0,0 +1,77 @@
+package union_find
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestDisjointSet(t *testing.T) {
+	d := InitDisjointSet[string]()
+	if d.Count() != 0 || d.Len() != 0 || d.Has("a") {
+		t.Fatalf("new set: Count() = %d, Len() = %d", d.Count(), d.Len())
+	}
+	if d.Find("a") != "a" || d.Size("a") != 1 || !d.Connected("a", "a") || d.Connected("a", "b") {
+		t.Error("unknown keys should be groups of their own")
+	}
+	if d.Len() != 0 {
+		t.Error("queries should not add keys")
+	}
+
+	d.Add("a", "b", "c", "d", "e", "a")
+	tests := []struct {
+		x, y   string
+		merged bool
+		size   int
+		count  int
+	}{
+		{x: "a", y: "b", merged: true, size: 2, count: 4},
+		{x: "c", y: "d", merged: true, size: 2, count: 3},
+		{x: "b", y: "a", merged: false, size: 2, count: 3},
+		{x: "b", y: "d", merged: true, size: 4, count: 2},
+		// f is added by its first use
+		{x: "f", y: "e", merged: true, size: 2, count: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.x+tt.y, func(t *testing.T) {
+			if got := d.Union(tt.x, tt.y); got != tt.merged {
+				t.Errorf("Union() = %v, want %v", got, tt.merged)
+			}
+			if !d.Connected(tt.x, tt.y) || d.Find(tt.x) != d.Find(tt.y) {
+				t.Error("keys are not connected after Union()")
+			}
+			if d.Size(tt.y) != tt.size || d.Count() != tt.count {
+				t.Errorf("Size() = %d, Count() = %d, want %d, %d", d.Size(tt.y), d.Count(), tt.size, tt.count)
+			}
+		})
+	}
+	if d.Len() != 6 || !d.Has("f") || d.Connected("a", "e") {
+		t.Errorf("Len() = %d", d.Len())
+	}
+
+	groups := d.Groups()
+	var got [][]string
+	for _, root := range slices.Sorted(maps.Keys(groups)) {
+		if d.Find(root) != root {
+			t.Errorf("group key %q is not a representative", root)
+		}
+		got = append(got, groups[root])
+	}
+	slices.SortFunc(got, func(a, b []string) int { return len(b) - len(a) })
+	want := [][]string{{"a", "b", "c", "d"}, {"e", "f"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Groups() = %v, want %v", got, want)
+	}
+}
+
+func TestDisjointSet_Chain(t *testing.T) {
+	type point struct{ x, y int }
+	d := InitDisjointSet[point]()
+	const n = 10000
+	for i := 1; i < n; i++ {
+		d.Union(point{i - 1, 0}, point{i, 0})
+	}
+	if d.Count() != 1 || d.Size(point{0, 0}) != n || !d.Connected(point{0, 0}, point{n - 1, 0}) {
+		t.Errorf("Count() = %d, Size() = %d", d.Count(), d.Size(point{0, 0}))
+	}
+}
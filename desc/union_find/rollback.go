@@ -0,0 +1,113 @@
+package union_find
+
+import (
+	"slices"
+
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+)
+
+// RollbackUnionFind 可回滚的并查集：只按大小合并、不做路径压缩，
+// 每次合并只修改一个根结点，因此可以按相反顺序撤销，Find为O(log n)
+type RollbackUnionFind struct {
+	uf UnionFind
+	// history 按顺序记录每次合并时被挂到另一个根下的根结点
+	history []int
+	// stamps[i] 是合并记录长度为i时的检查点编号。编号只增不减，
+	// 回滚到i以下后再合并会分配新编号，使旧检查点失效
+	stamps []int
+	next   int
+}
+
+// InitRollbackUnionFind 初始化n个元素
+func InitRollbackUnionFind(n int) *RollbackUnionFind {
+	return &RollbackUnionFind{uf: *InitUnionFind(n), stamps: []int{0}, next: 1}
+}
+
+// find 查找根结点，不压缩路径，以便回滚
+func (u *RollbackUnionFind) find(x int) int {
+	for x != u.uf.parent[x] {
+		x = u.uf.parent[x]
+	}
+	return x
+}
+
+// Find 查找元素x的根结点
+func (u *RollbackUnionFind) Find(x int) (int, error) {
+	if err := u.uf.check(x); err != nil {
+		return 0, err
+	}
+	return u.find(x), nil
+}
+
+// Union 合并两个分组
+func (u *RollbackUnionFind) Union(x, y int) error {
+	if err := u.uf.check(x, y); err != nil {
+		return err
+	}
+	xp, yp := u.find(x), u.find(y)
+	if xp == yp {
+		return nil
+	}
+	if u.uf.rank[yp] > u.uf.rank[xp] {
+		xp, yp = yp, xp
+	}
+	// 小分组的元素数量保持不变，回滚时用来恢复大分组的数量
+	u.uf.rank[xp] += u.uf.rank[yp]
+	u.uf.parent[yp] = xp
+	u.uf.count--
+	u.history = append(u.history, yp)
+	u.stamps = append(u.stamps, u.next)
+	u.next++
+	return nil
+}
+
+// Connected 判断x和y是否在同一个分组
+func (u *RollbackUnionFind) Connected(x, y int) (bool, error) {
+	if err := u.uf.check(x, y); err != nil {
+		return false, err
+	}
+	return u.find(x) == u.find(y), nil
+}
+
+// SizeOf 返回x所在分组的元素数量
+func (u *RollbackUnionFind) SizeOf(x int) (int, error) {
+	if err := u.uf.check(x); err != nil {
+		return 0, err
+	}
+	return u.uf.rank[u.find(x)], nil
+}
+
+// Count 返回分组数量
+func (u *RollbackUnionFind) Count() int {
+	return u.uf.count
+}
+
+// Snapshot 返回当前状态的检查点，供Rollback使用。检查点是一个不透明的编号，
+// 初始状态的检查点为0
+func (u *RollbackUnionFind) Snapshot() int {
+	return u.stamps[len(u.history)]
+}
+
+// Rollback 撤销检查点之后的所有合并。检查点可以嵌套：回滚到较早的检查点后，
+// 较晚的检查点失效，再使用会返回ErrInvalidCheckpoint
+func (u *RollbackUnionFind) Rollback(checkpoint int) error {
+	// stamps递增，二分查找检查点对应的合并记录长度
+	n, found := slices.BinarySearch(u.stamps, checkpoint)
+	if !found {
+		return ErrInvalidCheckpoint
+	}
+	u.stamps = u.stamps[:n+1]
+	for len(u.history) > n {
+		yp := u.history[len(u.history)-1]
+		u.history = u.history[:len(u.history)-1]
+		xp := u.uf.parent[yp]
+		u.uf.rank[xp] -= u.uf.rank[yp]
+		u.uf.parent[yp] = yp
+		u.uf.count++
+	}
+	return nil
+}
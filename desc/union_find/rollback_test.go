@@ -0,0 +1,149 @@
+package union_find
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// groups returns the root of every element, which identifies the state
+func groups(u *RollbackUnionFind) []int {
+	roots := make([]int, len(u.uf.parent))
+	for i := range roots {
+		roots[i] = u.find(i)
+	}
+	return roots
+}
+
+func TestRollbackUnionFind(t *testing.T) {
+	uf := InitRollbackUnionFind(6)
+	_ = uf.Union(0, 1)
+	outer := uf.Snapshot()
+	outerState := groups(uf)
+
+	_ = uf.Union(2, 3)
+	_ = uf.Union(1, 3)
+	inner := uf.Snapshot()
+	innerState := groups(uf)
+	_ = uf.Union(4, 5)
+	_ = uf.Union(0, 4)
+	// a Union inside one group is not recorded
+	_ = uf.Union(5, 1)
+	if uf.Count() != 1 {
+		t.Fatalf("Count() = %d, want 1", uf.Count())
+	}
+	if size, _ := uf.SizeOf(5); size != 6 {
+		t.Fatalf("SizeOf() = %d, want 6", size)
+	}
+
+	if err := uf.Rollback(inner); err != nil {
+		t.Fatal(err)
+	}
+	if got := groups(uf); !slices.Equal(got, innerState) || uf.Count() != 3 {
+		t.Errorf("after the inner rollback roots = %v, want %v", got, innerState)
+	}
+	if size, _ := uf.SizeOf(2); size != 4 {
+		t.Errorf("SizeOf() = %d, want 4", size)
+	}
+	// the inner checkpoint can be reused until an outer rollback
+	_ = uf.Union(4, 5)
+	if err := uf.Rollback(inner); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := uf.Connected(4, 5); ok {
+		t.Error("4 and 5 still connected")
+	}
+
+	if err := uf.Rollback(outer); err != nil {
+		t.Fatal(err)
+	}
+	if got := groups(uf); !slices.Equal(got, outerState) || uf.Count() != 5 {
+		t.Errorf("after the outer rollback roots = %v, want %v", got, outerState)
+	}
+	if err := uf.Rollback(inner); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Rollback() to a discarded checkpoint error = %v", err)
+	}
+	// new unions after the outer rollback bring the history back to the
+	// length of the inner checkpoint, which must still be refused
+	_ = uf.Union(4, 5)
+	_ = uf.Union(2, 3)
+	if err := uf.Rollback(inner); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Rollback() to a stale checkpoint error = %v", err)
+	}
+	if ok, _ := uf.Connected(4, 5); !ok {
+		t.Error("a refused rollback changed the state")
+	}
+	if err := uf.Rollback(outer); err != nil {
+		t.Fatal(err)
+	}
+	if err := uf.Rollback(-1); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("Rollback(-1) error = %v", err)
+	}
+	if err := uf.Rollback(0); err != nil || uf.Count() != 6 {
+		t.Errorf("Rollback(0) = %v, Count() = %d", err, uf.Count())
+	}
+}
+
+func TestRollbackUnionFind_OutOfRange(t *testing.T) {
+	uf := InitRollbackUnionFind(2)
+	if _, err := uf.Find(2); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Find() error = %v", err)
+	}
+	if err := uf.Union(0, 2); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Union() error = %v", err)
+	}
+	if _, err := uf.Connected(-1, 0); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Connected() error = %v", err)
+	}
+	if _, err := uf.SizeOf(3); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SizeOf() error = %v", err)
+	}
+	if uf.Snapshot() != 0 {
+		t.Error("failed calls were recorded")
+	}
+}
+
+// TestRollbackUnionFind_Backtracking explores random unions depth first,
+// checking after every rollback that the state matches the one saved at the
+// checkpoint and that the trees stay shallow
+func TestRollbackUnionFind_Backtracking(t *testing.T) {
+	const n = 64
+	r := rand.New(rand.NewPCG(9, 10))
+	uf := InitRollbackUnionFind(n)
+	var explore func(depth int)
+	explore = func(depth int) {
+		if depth == 0 {
+			return
+		}
+		for branch := 0; branch < 3; branch++ {
+			cp := uf.Snapshot()
+			state, count := groups(uf), uf.Count()
+			for i := 0; i < 8; i++ {
+				_ = uf.Union(r.IntN(n), r.IntN(n))
+			}
+			explore(depth - 1)
+			if err := uf.Rollback(cp); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(groups(uf), state) || uf.Count() != count {
+				t.Fatalf("rollback at depth %d did not restore the state", depth)
+			}
+		}
+	}
+	explore(4)
+
+	// union by size keeps every path within log2(n) links
+	for i := 0; i < 10*n; i++ {
+		_ = uf.Union(r.IntN(n), r.IntN(n))
+	}
+	for x := 0; x < n; x++ {
+		depth := 0
+		for y := x; y != uf.uf.parent[y]; y = uf.uf.parent[y] {
+			depth++
+		}
+		if depth > 6 {
+			t.Errorf("element %d is %d links deep", x, depth)
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package union_find
 
+import (
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrOutOfRange = errors.New("index out of range")
+)
+
 type UnionFind struct {
 	parent []int
 	rank   []int
@@ -20,30 +28,58 @@ func InitUnionFind(n int) *UnionFind {
 	return uf
 }
 
-// Find 递归查找元素x的根结点，查找的同时将该组所有元素(遍历到的)都直接指向根节点（路径压缩）
-func (u *UnionFind) Find(x int) int {
-	if u.parent[x] == x {
-		return x
+// add 添加一个新元素，自成一组，返回其下标
+func (u *UnionFind) add() int {
+	x := len(u.parent)
+	u.parent = append(u.parent, x)
+	u.rank = append(u.rank, 1)
+	u.count++
+	return x
+}
+
+func (u *UnionFind) check(xs ...int) error {
+	for _, x := range xs {
+		if x < 0 || x >= len(u.parent) {
+			return ErrOutOfRange
+		}
 	}
-	u.parent[x] = u.Find(u.parent[x])
-	return u.parent[x]
+	return nil
 }
 
-//func (u *UnionFind) Find1(x int) int {
-//	for x != u.parent[x] {
-//		u.parent[x] = u.parent[u.parent[x]]
-//		x = u.parent[x]
-//	}
-//	return x
-//}
+// Find 查找元素x的根结点，x越界时返回ErrOutOfRange
+func (u *UnionFind) Find(x int) (int, error) {
+	if err := u.check(x); err != nil {
+		return 0, err
+	}
+	return u.find(x), nil
+}
+
+// find 迭代查找根结点，查找的同时让遍历到的元素指向祖父结点（路径减半），
+// 不会像递归那样在很长的链上爆栈
+func (u *UnionFind) find(x int) int {
+	for x != u.parent[x] {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+	}
+	return x
+}
+
+// Union 合并两个分组，x或y越界时返回ErrOutOfRange
+func (u *UnionFind) Union(x, y int) error {
+	if err := u.check(x, y); err != nil {
+		return err
+	}
+	u.union(x, y)
+	return nil
+}
 
-// Union 合并两个分组
-func (u *UnionFind) Union(x, y int) {
-	xp := u.Find(x)
-	yp := u.Find(y)
+// union 合并两个分组，返回是否真的发生了合并
+func (u *UnionFind) union(x, y int) bool {
+	xp := u.find(x)
+	yp := u.find(y)
 	if xp == yp {
 		// 已经是同一个分组了，直接返回
-		return
+		return false
 	}
 	// 我们将小分组合并到大分组（这一步不是必须的）
 	if u.rank[yp] > u.rank[xp] {
@@ -58,4 +94,31 @@ func (u *UnionFind) Union(x, y int) {
 	u.parent[yp] = xp
 	// 总的分组数减少
 	u.count--
+	return true
+}
+
+// Connected 判断x和y是否在同一个分组
+func (u *UnionFind) Connected(x, y int) (bool, error) {
+	if err := u.check(x, y); err != nil {
+		return false, err
+	}
+	return u.find(x) == u.find(y), nil
+}
+
+// SizeOf 返回x所在分组的元素数量
+func (u *UnionFind) SizeOf(x int) (int, error) {
+	if err := u.check(x); err != nil {
+		return 0, err
+	}
+	return u.rank[u.find(x)], nil
+}
+
+// Count 返回分组数量
+func (u *UnionFind) Count() int {
+	return u.count
+}
+
+// Len 返回元素数量
+func (u *UnionFind) Len() int {
+	return len(u.parent)
 }
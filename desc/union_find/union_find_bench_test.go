@@ -0,0 +1,56 @@
+package union_find
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+const benchSize = 1 << 16
+
+func BenchmarkUnionFind_Union(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	uf := InitUnionFind(benchSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if uf.Count() == 1 {
+			b.StopTimer()
+			uf = InitUnionFind(benchSize)
+			b.StartTimer()
+		}
+		_ = uf.Union(r.IntN(benchSize), r.IntN(benchSize))
+	}
+}
+
+func BenchmarkUnionFind_Find(b *testing.B) {
+	r := rand.New(rand.NewPCG(3, 4))
+	uf := InitUnionFind(benchSize)
+	for i := 0; i < benchSize/2; i++ {
+		_ = uf.Union(r.IntN(benchSize), r.IntN(benchSize))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = uf.Find(i % benchSize)
+	}
+}
+
+// BenchmarkUnionFind_FindChain measures the first Find on a long chain, the
+// case where a recursive Find needs deep stacks
+func BenchmarkUnionFind_FindChain(b *testing.B) {
+	uf := InitUnionFind(benchSize)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j := 1; j < benchSize; j++ {
+			uf.parent[j] = j - 1
+		}
+		b.StartTimer()
+		_, _ = uf.Find(benchSize - 1)
+	}
+}
+
+func BenchmarkDisjointSet_Union(b *testing.B) {
+	r := rand.New(rand.NewPCG(5, 6))
+	d := InitDisjointSet[int]()
+	for i := 0; i < b.N; i++ {
+		d.Union(r.IntN(benchSize), r.IntN(benchSize))
+	}
+}
@@ -1,40 +1,85 @@
 package union_find
 
 import (
+	"errors"
 	"testing"
 )
 
-// todo: 有缘就补上
 func TestUnionFind(t *testing.T) {
 	n := 10
 	uf := InitUnionFind(n)
 
 	// Test initial state
-	if uf.count != n {
-		t.Errorf("Expected initial count to be %d, got %d", n, uf.count)
+	if uf.Count() != n || uf.Len() != n {
+		t.Errorf("Expected initial count to be %d, got %d", n, uf.Count())
 	}
 	for i := 0; i < n; i++ {
-		if uf.Find(i) != i {
+		if root, err := uf.Find(i); err != nil || root != i {
 			t.Errorf("Expected element %d to be its own root", i)
 		}
 	}
 
 	// Test union and find
-	uf.Union(0, 1)
-	if uf.Find(0) != uf.Find(1) {
+	if err := uf.Union(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := uf.Connected(0, 1); !ok {
 		t.Errorf("Elements 0 and 1 should be in the same group")
 	}
-	if uf.count != n-1 {
-		t.Errorf("Expected count to be %d, got %d", n-1, uf.count)
+	if uf.Count() != n-1 {
+		t.Errorf("Expected count to be %d, got %d", n-1, uf.Count())
 	}
-	uf.Union(1, 1)
+	_ = uf.Union(1, 1)
 
 	// Test rank
-	uf.Union(2, 0)
-	if uf.rank[uf.Find(0)] != 3 {
-		t.Errorf("Expected rank of group containing element 0 to be 3, got %d", uf.rank[uf.Find(0)])
+	_ = uf.Union(2, 0)
+	if size, _ := uf.SizeOf(0); size != 3 {
+		t.Errorf("Expected rank of group containing element 0 to be 3, got %d", size)
+	}
+	if ok, _ := uf.Connected(2, 3); ok {
+		t.Errorf("Elements 2 and 3 should not be in the same group")
+	}
+}
+
+func TestUnionFind_OutOfRange(t *testing.T) {
+	uf := InitUnionFind(3)
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "Find negative", call: func() error { _, err := uf.Find(-1); return err }},
+		{name: "Find too large", call: func() error { _, err := uf.Find(3); return err }},
+		{name: "Union x", call: func() error { return uf.Union(3, 0) }},
+		{name: "Union y", call: func() error { return uf.Union(0, -2) }},
+		{name: "Connected", call: func() error { _, err := uf.Connected(0, 5); return err }},
+		{name: "SizeOf", call: func() error { _, err := uf.SizeOf(7); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrOutOfRange) {
+				t.Errorf("error = %v, want %v", err, ErrOutOfRange)
+			}
+		})
+	}
+	if uf.Count() != 3 {
+		t.Errorf("failed calls changed Count() to %d", uf.Count())
+	}
+}
+
+// TestUnionFind_LongChain builds a chain one link at a time without letting
+// union by size flatten it, then finds its tail, which must not overflow
+// the stack
+func TestUnionFind_LongChain(t *testing.T) {
+	const n = 1 << 20
+	uf := InitUnionFind(n)
+	for i := 1; i < n; i++ {
+		uf.parent[i] = i - 1
+	}
+	if root, err := uf.Find(n - 1); err != nil || root != 0 {
+		t.Fatalf("Find() = %d, %v", root, err)
+	}
+	// path halving has shortened the chain
+	if uf.parent[n-1] == n-2 {
+		t.Error("Find() did not compress the path")
 	}
-	//if uf.rank[uf.Find1(0)] != 3 {
-	//	t.Errorf("Expected rank of group containing element 0 to be 3, got %d", uf.rank[uf.Find1(0)])
-	//}
 }
@@ -0,0 +1,132 @@
+package union_find
+
+import (
+	"github.com/victorwong171/go-utils/utils"
+	"gopkg.in/errgo.v2/errors"
+)
+
+var (
+	ErrConflict     = errors.New("relation conflicts with a known one")
+	ErrNotConnected = errors.New("elements are not connected")
+)
+
+// WeightedUnionFind 带权并查集：每个元素有一个相对于根结点的势能差，
+// Union(x, y, w) 记录 x 比 y 大 w，Diff(x, y) 给出已知关系推出的差值
+type WeightedUnionFind[W utils.NUMBER] struct {
+	uf UnionFind
+	// diff[x] 是 x 与其父结点的势能差
+	diff []W
+	// tolerance 是判断两个差值相等时允许的误差，用于浮点数
+	tolerance W
+}
+
+// InitWeightedUnionFind 初始化n个元素，tolerance为比较差值时允许的误差，整数类型传0
+func InitWeightedUnionFind[W utils.NUMBER](n int, tolerance W) *WeightedUnionFind[W] {
+	return &WeightedUnionFind[W]{
+		uf:        *InitUnionFind(n),
+		diff:      make([]W, n),
+		tolerance: tolerance,
+	}
+}
+
+// find 返回x的根结点以及x与根结点的势能差，同时把路径上的元素都直接指向根结点
+func (u *WeightedUnionFind[W]) find(x int) (int, W) {
+	// 第一遍：找到根结点并累加势能差
+	root := x
+	var total W
+	for root != u.uf.parent[root] {
+		total += u.diff[root]
+		root = u.uf.parent[root]
+	}
+	// 第二遍：路径压缩，每个元素的势能差改为相对于根结点
+	rest := total
+	for x != root {
+		next, d := u.uf.parent[x], u.diff[x]
+		u.uf.parent[x], u.diff[x] = root, rest
+		rest -= d
+		x = next
+	}
+	return root, total
+}
+
+// Find 查找元素x的根结点
+func (u *WeightedUnionFind[W]) Find(x int) (int, error) {
+	if err := u.uf.check(x); err != nil {
+		return 0, err
+	}
+	root, _ := u.find(x)
+	return root, nil
+}
+
+// Union 记录 x 比 y 大 w。x和y已经连通时，若已知的差值与w不符则返回ErrConflict，
+// 并查集保持不变
+func (u *WeightedUnionFind[W]) Union(x, y int, w W) error {
+	if err := u.uf.check(x, y); err != nil {
+		return err
+	}
+	rx, dx := u.find(x)
+	ry, dy := u.find(y)
+	if rx == ry {
+		if !u.equal(dx-dy, w) {
+			return ErrConflict
+		}
+		return nil
+	}
+	// 根结点之间的势能差：rx 比 ry 大 d
+	d := w - dx + dy
+	// 小分组合并到大分组
+	if u.uf.rank[rx] > u.uf.rank[ry] {
+		rx, ry, d = ry, rx, -d
+	}
+	u.uf.parent[rx] = ry
+	u.diff[rx] = d
+	u.uf.rank[ry] += u.uf.rank[rx]
+	u.uf.rank[rx] = 0
+	u.uf.count--
+	return nil
+}
+
+func (u *WeightedUnionFind[W]) equal(a, b W) bool {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	return d <= u.tolerance
+}
+
+// Diff 返回 x 比 y 大多少，x和y不连通时返回ErrNotConnected
+func (u *WeightedUnionFind[W]) Diff(x, y int) (W, error) {
+	if err := u.uf.check(x, y); err != nil {
+		return 0, err
+	}
+	rx, dx := u.find(x)
+	ry, dy := u.find(y)
+	if rx != ry {
+		return 0, ErrNotConnected
+	}
+	return dx - dy, nil
+}
+
+// Connected 判断x和y是否在同一个分组
+func (u *WeightedUnionFind[W]) Connected(x, y int) (bool, error) {
+	if err := u.uf.check(x, y); err != nil {
+		return false, err
+	}
+	rx, _ := u.find(x)
+	ry, _ := u.find(y)
+	return rx == ry, nil
+}
+
+// SizeOf 返回x所在分组的元素数量
+func (u *WeightedUnionFind[W]) SizeOf(x int) (int, error) {
+	root, err := u.Find(x)
+	if err != nil {
+		return 0, err
+	}
+	return u.uf.rank[root], nil
+}
+
+// Count 返回分组数量
+func (u *WeightedUnionFind[W]) Count() int {
+	return u.uf.count
+}
@@ -0,0 +1,132 @@
+package union_find
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestWeightedUnionFind(t *testing.T) {
+	uf := InitWeightedUnionFind[int](6, 0)
+	// 0 = 1 + 3, 1 = 2 + 4, 3 = 4 - 2
+	steps := []struct {
+		x, y, w int
+		err     error
+	}{
+		{x: 0, y: 1, w: 3},
+		{x: 1, y: 2, w: 4},
+		{x: 3, y: 4, w: -2},
+		{x: 0, y: 2, w: 7},
+		{x: 0, y: 2, w: 8, err: ErrConflict},
+		{x: 2, y: 4, w: 5},
+		{x: 1, y: 3, w: 0, err: ErrConflict},
+		{x: 6, y: 0, w: 1, err: ErrOutOfRange},
+	}
+	for _, s := range steps {
+		if err := uf.Union(s.x, s.y, s.w); !errors.Is(err, s.err) {
+			t.Errorf("Union(%d, %d, %d) error = %v, want %v", s.x, s.y, s.w, err, s.err)
+		}
+	}
+	if uf.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", uf.Count())
+	}
+
+	tests := []struct {
+		x, y int
+		want int
+		err  error
+	}{
+		{x: 0, y: 2, want: 7},
+		{x: 2, y: 0, want: -7},
+		{x: 0, y: 4, want: 12},
+		{x: 3, y: 0, want: -14},
+		{x: 4, y: 4, want: 0},
+		{x: 0, y: 5, err: ErrNotConnected},
+		{x: -1, y: 0, err: ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		got, err := uf.Diff(tt.x, tt.y)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Diff(%d, %d) = %d, %v, want %d, %v", tt.x, tt.y, got, err, tt.want, tt.err)
+		}
+	}
+
+	if ok, _ := uf.Connected(1, 3); !ok {
+		t.Error("1 and 3 should be connected")
+	}
+	if _, err := uf.Connected(0, 9); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Connected() error = %v", err)
+	}
+	if size, _ := uf.SizeOf(4); size != 5 {
+		t.Errorf("SizeOf() = %d, want 5", size)
+	}
+	if _, err := uf.SizeOf(9); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SizeOf() error = %v", err)
+	}
+	if root, err := uf.Find(5); root != 5 || err != nil {
+		t.Errorf("Find() = %d, %v", root, err)
+	}
+}
+
+// TestWeightedUnionFind_Rates checks currency conversion in log space, where
+// floating point rounding needs the tolerance
+func TestWeightedUnionFind_Rates(t *testing.T) {
+	const (
+		usd = iota
+		eur
+		jpy
+		gbp
+	)
+	uf := InitWeightedUnionFind(4, 1e-9)
+	rate := func(x, y int, r float64) error { return uf.Union(x, y, math.Log(r)) }
+	if err := rate(eur, usd, 1.1); err != nil {
+		t.Fatal(err)
+	}
+	if err := rate(usd, jpy, 0.0067); err != nil {
+		t.Fatal(err)
+	}
+	// consistent up to rounding
+	if err := rate(eur, jpy, 1.1*0.0067); err != nil {
+		t.Errorf("consistent rate rejected: %v", err)
+	}
+	if err := rate(jpy, eur, 140); !errors.Is(err, ErrConflict) {
+		t.Errorf("conflicting rate error = %v", err)
+	}
+	d, err := uf.Diff(jpy, eur)
+	if err != nil || math.Abs(math.Exp(d)-1/(1.1*0.0067)) > 1e-6 {
+		t.Errorf("jpy per eur = %g, %v", math.Exp(d), err)
+	}
+	if _, err = uf.Diff(gbp, usd); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Diff() error = %v", err)
+	}
+}
+
+// TestWeightedUnionFind_Random assigns hidden values, unions random pairs
+// with their true difference and checks every answer against them
+func TestWeightedUnionFind_Random(t *testing.T) {
+	const n = 2000
+	r := rand.New(rand.NewPCG(7, 8))
+	value := make([]int64, n)
+	for i := range value {
+		value[i] = r.Int64N(1000000)
+	}
+	uf := InitWeightedUnionFind[int64](n, 0)
+	for i := 0; i < 3*n; i++ {
+		x, y := r.IntN(n), r.IntN(n)
+		if err := uf.Union(x, y, value[x]-value[y]); err != nil {
+			t.Fatalf("Union(%d, %d) error = %v", x, y, err)
+		}
+		if i%7 == 0 {
+			if err := uf.Union(x, y, value[x]-value[y]+1); x != y && !errors.Is(err, ErrConflict) {
+				t.Fatalf("wrong relation accepted, error = %v", err)
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		x, y := r.IntN(n), r.IntN(n)
+		if d, err := uf.Diff(x, y); err == nil && d != value[x]-value[y] {
+			t.Fatalf("Diff(%d, %d) = %d, want %d", x, y, d, value[x]-value[y])
+		}
+	}
+}